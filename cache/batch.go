@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// BatchCache is implemented by backends that can read, write and delete
+// several keys in one call.
+//
+// GetMulti returns only the keys that were found; missing and expired keys
+// are left out of the map rather than reported as errors. DeleteMulti
+// ignores keys that do not exist.
+type BatchCache interface {
+	GetMulti(keys []string) (map[string]interface{}, error)
+	SetMulti(items map[string]interface{}, ttl time.Duration) error
+	DeleteMulti(keys []string) error
+}
+
+// GetMulti fetches keys from c, using BatchCache when c implements it
+// and one Get per key otherwise.
+func GetMulti(c Cache, keys []string) (map[string]interface{}, error) {
+	if bc, ok := c.(BatchCache); ok {
+		return bc.GetMulti(keys)
+	}
+
+	result := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		val, err := c.Get(key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[key] = val
+	}
+	return result, nil
+}
+
+// SetMulti stores items in c with the same TTL, using BatchCache when
+// c implements it and one Set per key otherwise.
+func SetMulti(c Cache, items map[string]interface{}, ttl time.Duration) error {
+	if bc, ok := c.(BatchCache); ok {
+		return bc.SetMulti(items, ttl)
+	}
+
+	for key, val := range items {
+		if err := c.Set(key, val, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteMulti removes keys from c, using BatchCache when c implements it
+// and one Delete per key otherwise. Missing keys are ignored.
+func DeleteMulti(c Cache, keys []string) error {
+	if bc, ok := c.(BatchCache); ok {
+		return bc.DeleteMulti(keys)
+	}
+
+	for _, key := range keys {
+		if err := c.Delete(key); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
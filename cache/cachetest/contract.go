@@ -0,0 +1,92 @@
+// Package cachetest provides helpers for testing cache.Cache implementations.
+package cachetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
+)
+
+// RunContract checks that c follows the miss and delete contract documented
+// in package cache. c is cleared before each subtest.
+func RunContract(t *testing.T, c cache.Cache) {
+	t.Helper()
+	runContract(t, c, time.Sleep)
+}
+
+// RunContractWithClock is like RunContract for a cache that reads the time
+// from clock. Expiry is checked by advancing clock instead of sleeping.
+func RunContractWithClock(t *testing.T, c cache.Cache, clock *FakeClock) {
+	t.Helper()
+	runContract(t, c, clock.Advance)
+}
+
+// runContract runs the contract, calling wait to let a TTL elapse
+func runContract(t *testing.T, c cache.Cache, wait func(time.Duration)) {
+	t.Helper()
+
+	reset := func(t *testing.T) {
+		t.Helper()
+		if err := c.Clear(); err != nil {
+			t.Fatalf("Clear failed: %v", err)
+		}
+	}
+
+	t.Run("GetMissing", func(t *testing.T) {
+		reset(t)
+
+		_, err := c.Get("contract:missing")
+		if !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("Get on missing key: expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("GetExpired", func(t *testing.T) {
+		reset(t)
+
+		if err := c.Set("contract:ttl", "value", 1*time.Second); err != nil {
+			t.Fatal(err)
+		}
+		wait(2 * time.Second)
+
+		_, err := c.Get("contract:ttl")
+		if !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("Get on expired key: expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("DeleteExisting", func(t *testing.T) {
+		reset(t)
+
+		if err := c.Set("contract:del", "value", 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Delete("contract:del"); err != nil {
+			t.Fatalf("Delete on existing key: %v", err)
+		}
+
+		_, err := c.Get("contract:del")
+		if !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("Get after Delete: expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("DeleteMissing", func(t *testing.T) {
+		reset(t)
+
+		err := c.Delete("contract:missing")
+		if !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("Delete on missing key: expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ClearEmpty", func(t *testing.T) {
+		reset(t)
+
+		if err := c.Clear(); err != nil {
+			t.Fatalf("Clear on empty cache: %v", err)
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package cache
+
+import "time"
+
+// Clock tells a cache the current time, so TTL expiry can be tested
+// without waiting for real time to pass.
+//
+// Only caches that track expiry themselves, like the in-memory LRU, take
+// a Clock. Wrappers such as Tiered and Typed pass TTLs through unchanged,
+// so the clock of the cache underneath governs when their entries expire.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by time.Now.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
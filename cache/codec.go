@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ErrUnsupportedValue is returned by a Codec that cannot encode or
+// decode the given value.
+var ErrUnsupportedValue = errors.New("cache: value not supported by codec")
+
+// Codec serializes values for backends that store bytes.
+// Unmarshal decodes into v, which must be a non-nil pointer.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec encodes values as JSON. It is the default for Redis and Memcached.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// GobCodec encodes values with encoding/gob.
+// Values are encoded as interface values so they can be decoded without
+// knowing the type; custom types must be registered with gob.Register.
+type GobCodec struct{}
+
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, v interface{}) error {
+	var decoded interface{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+	return assign(v, decoded)
+}
+
+// RawCodec passes []byte and string values through unchanged.
+// Any other type is rejected with ErrUnsupportedValue. Decoding into
+// an interface{} yields a []byte.
+type RawCodec struct{}
+
+func (RawCodec) Marshal(v interface{}) ([]byte, error) {
+	switch val := v.(type) {
+	case []byte:
+		return val, nil
+	case string:
+		return []byte(val), nil
+	}
+	return nil, fmt.Errorf("%w: raw codec cannot encode %T", ErrUnsupportedValue, v)
+}
+
+func (RawCodec) Unmarshal(data []byte, v interface{}) error {
+	switch dst := v.(type) {
+	case *[]byte:
+		*dst = append([]byte(nil), data...)
+	case *string:
+		*dst = string(data)
+	case *interface{}:
+		*dst = append([]byte(nil), data...)
+	default:
+		return fmt.Errorf("%w: raw codec cannot decode into %T", ErrUnsupportedValue, v)
+	}
+	return nil
+}
+
+// MsgpackCodec encodes values as MessagePack, a compact binary format.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return msgpack.Unmarshal(data, v)
+}
+
+// assign stores val into the variable dst points to.
+func assign(dst interface{}, val interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: decode target %T is not a non-nil pointer", ErrUnsupportedValue, dst)
+	}
+
+	elem := rv.Elem()
+	if val == nil {
+		elem.Set(reflect.Zero(elem.Type()))
+		return nil
+	}
+
+	src := reflect.ValueOf(val)
+	if !src.Type().AssignableTo(elem.Type()) {
+		return fmt.Errorf("%w: cannot decode %T into %s", ErrTypeMismatch, val, elem.Type())
+	}
+	elem.Set(src)
+	return nil
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"encoding/gob"
+	"errors"
+	"testing"
+)
+
+type codecUser struct {
+	Name string
+	Age  int
+}
+
+func init() {
+	// GobCodec needs concrete types registered
+	gob.Register(codecUser{})
+}
+
+// TestCodecs_RoundTrip checks every codec returns the stored type
+func TestCodecs_RoundTrip(t *testing.T) {
+
+	codecs := map[string]Codec{
+		"json":    JSONCodec{},
+		"gob":     GobCodec{},
+		"msgpack": MsgpackCodec{},
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+
+			data, err := codec.Marshal(codecUser{"alice", 30})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var u codecUser
+			if err := codec.Unmarshal(data, &u); err != nil || u != (codecUser{"alice", 30}) {
+				t.Fatalf("struct round trip failed: %+v, %v", u, err)
+			}
+
+			// a numeric-looking string must stay a string
+			data, err = codec.Marshal("123")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var s interface{}
+			if err := codec.Unmarshal(data, &s); err != nil || s != "123" {
+				t.Fatalf("string round trip failed: %#v, %v", s, err)
+			}
+		})
+	}
+}
+
+// TestCodecs_EncodeErrors checks unsupported values are reported
+func TestCodecs_EncodeErrors(t *testing.T) {
+
+	if _, err := (JSONCodec{}).Marshal(make(chan int)); err == nil {
+		t.Fatal("Expected JSON encode error for channel")
+	}
+
+	if _, err := (RawCodec{}).Marshal(42); !errors.Is(err, ErrUnsupportedValue) {
+		t.Fatalf("Expected ErrUnsupportedValue, got %v", err)
+	}
+}
+
+// TestRawCodec checks []byte and string passthrough
+func TestRawCodec(t *testing.T) {
+
+	codec := RawCodec{}
+
+	data, err := codec.Marshal("hello")
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("Raw Marshal failed: %q, %v", data, err)
+	}
+
+	var s string
+	if err := codec.Unmarshal(data, &s); err != nil || s != "hello" {
+		t.Fatalf("Raw Unmarshal into string failed: %q, %v", s, err)
+	}
+
+	var b []byte
+	if err := codec.Unmarshal([]byte{1, 2, 3}, &b); err != nil || len(b) != 3 {
+		t.Fatalf("Raw Unmarshal into []byte failed: %v, %v", b, err)
+	}
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// contextAdapter wraps a plain Cache and checks the context
+// before and after every call.
+type contextAdapter struct {
+	Cache
+}
+
+// WithContext returns a ContextCache for c.
+// If c already implements ContextCache it is returned as is, otherwise
+// the calls are wrapped so that a done context is reported as an error.
+// The wrapped backend call itself is not interrupted.
+func WithContext(c Cache) ContextCache {
+	if cc, ok := c.(ContextCache); ok {
+		return cc
+	}
+	return contextAdapter{c}
+}
+
+func (a contextAdapter) GetCtx(ctx context.Context, key string) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	val, err := a.Get(key)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	return val, err
+}
+
+func (a contextAdapter) SetCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := a.Set(key, value, ttl); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (a contextAdapter) DeleteCtx(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := a.Delete(key); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (a contextAdapter) ClearCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := a.Clear(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,26 @@
+package cache
+
+import "errors"
+
+// Miss and delete contract shared by every backend:
+//
+//   - Get on a missing key returns an error matching ErrNotFound.
+//   - Get on a key whose TTL has elapsed returns ErrNotFound, or ErrExpired
+//     when the backend can tell the two apart. ErrExpired matches
+//     ErrNotFound with errors.Is, so checking ErrNotFound covers both.
+//   - Delete on a missing key returns an error matching ErrNotFound.
+//   - Clear on an empty cache returns nil.
+var (
+	// ErrNotFound is returned when a key is not present in the cache.
+	ErrNotFound = errors.New("cache: key not found")
+
+	// ErrExpired is returned when a key was present but its TTL has elapsed.
+	ErrExpired error = expiredError{}
+)
+
+type expiredError struct{}
+
+func (expiredError) Error() string { return "cache: key expired" }
+
+// Is reports an expired key as a miss as well.
+func (expiredError) Is(target error) bool { return target == ErrNotFound }
@@ -1,6 +1,9 @@
 package cache
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Cache defines the unified caching interface
 type Cache interface {
@@ -18,3 +21,20 @@ type Cache interface {
 	// Clear removes all entries from the cache.
 	Clear() error
 }
+
+// ContextCache is the context-aware variant of Cache.
+// Deadlines and cancellation of ctx are honoured by the backend.
+type ContextCache interface {
+
+	// GetCtx retrieves a value by key.
+	GetCtx(ctx context.Context, key string) (interface{}, error)
+
+	// SetCtx stores a key-value pair with optional TTL.
+	SetCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+
+	// DeleteCtx removes a key from the cache.
+	DeleteCtx(ctx context.Context, key string) error
+
+	// ClearCtx removes all entries from the cache.
+	ClearCtx(ctx context.Context) error
+}
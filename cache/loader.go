@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// errLoaderPanicked is reported to callers waiting on a loader that panicked.
+var errLoaderPanicked = errors.New("cache: loader panicked")
+
+// GetOrLoad returns the value for key from c. On a miss it calls loader,
+// stores the result with ttl and returns it.
+//
+// Concurrent misses for the same key on the same cache share one loader
+// call. Loader errors are returned to every waiting caller and nothing is
+// cached. Get errors other than a miss are returned without calling loader.
+// If storing the loaded value fails, the value is returned together with
+// that error.
+//
+// c is used as a map key to group callers, so its dynamic type must be
+// comparable; every backend in this module is a pointer type.
+func GetOrLoad(c Cache, key string, ttl time.Duration, loader func() (interface{}, error)) (interface{}, error) {
+	val, err := c.Get(key)
+	if err == nil {
+		return val, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	return loads.do(flightKey{c, key}, func() (interface{}, error) {
+		val, err := loader()
+		if err != nil {
+			return nil, err
+		}
+		return val, c.Set(key, val, ttl)
+	})
+}
+
+// GetOrLoad is the typed form of cache.GetOrLoad. A call that joins an
+// in-flight untyped GetOrLoad for the same key whose value is not a V
+// returns an error matching ErrTypeMismatch.
+func (t *Typed[V]) GetOrLoad(key string, ttl time.Duration, loader func() (V, error)) (V, error) {
+	v, err := t.Get(key)
+	if err == nil || !errors.Is(err, ErrNotFound) {
+		return v, err
+	}
+
+	val, err := loads.do(flightKey{t.c, key}, func() (interface{}, error) {
+		v, err := loader()
+		if err != nil {
+			return nil, err
+		}
+		return v, t.c.Set(key, v, ttl)
+	})
+	if loaded, ok := val.(V); ok {
+		v = loaded
+	} else if val != nil {
+		// joined a load started by an untyped GetOrLoad for the same key
+		return v, fmt.Errorf("%w: key %q loaded %T, want %T", ErrTypeMismatch, key, val, v)
+	}
+	return v, err
+}
+
+// loads collapses concurrent loader calls across all caches.
+var loads flightGroup
+
+type flightKey struct {
+	c   Cache
+	key string
+}
+
+// flight is one in-progress loader call.
+type flight struct {
+	wg  sync.WaitGroup
+	val interface{}
+	err error
+}
+
+// flightGroup runs at most one function per key at a time and hands its
+// result to every caller that asked for the same key meanwhile.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[flightKey]*flight
+}
+
+func (g *flightGroup) do(k flightKey, fn func() (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = make(map[flightKey]*flight)
+	}
+	if f, ok := g.flights[k]; ok {
+		g.mu.Unlock()
+		f.wg.Wait()
+		return f.val, f.err
+	}
+
+	f := &flight{err: errLoaderPanicked}
+	f.wg.Add(1)
+	g.flights[k] = f
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.flights, k)
+		g.mu.Unlock()
+		f.wg.Done()
+	}()
+
+	f.val, f.err = fn()
+	return f.val, f.err
+}
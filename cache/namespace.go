@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PrefixClearer is implemented by backends that can remove every key
+// starting with a prefix, leaving other keys alone.
+type PrefixClearer interface {
+	ClearPrefix(prefix string) error
+}
+
+// Namespace is a view of a Cache in which every key is stored under a
+// prefix, so several users can share one backend without colliding.
+type Namespace struct {
+	c      Cache
+	prefix string
+}
+
+// Namespaced returns a view of c that prefixes every key with prefix,
+// such as "svc:orders:". Namespaces nest: a namespace of a namespace
+// uses both prefixes, outermost first.
+//
+// Clear removes only the keys of the namespace and needs a backend
+// implementing PrefixClearer; on other backends it returns an error
+// matching errors.ErrUnsupported rather than clearing everything.
+func Namespaced(c Cache, prefix string) *Namespace {
+	if ns, ok := c.(*Namespace); ok {
+		return &Namespace{c: ns.c, prefix: ns.prefix + prefix}
+	}
+	return &Namespace{c: c, prefix: prefix}
+}
+
+// Prefix returns the full prefix added to keys, including outer namespaces.
+func (ns *Namespace) Prefix() string { return ns.prefix }
+
+// Unwrap returns the cache the keys are stored in.
+func (ns *Namespace) Unwrap() Cache { return ns.c }
+
+// Get retrieves the value for key within the namespace.
+func (ns *Namespace) Get(key string) (interface{}, error) {
+	return ns.c.Get(ns.prefix + key)
+}
+
+// Set stores value for key within the namespace, with optional TTL.
+func (ns *Namespace) Set(key string, value interface{}, ttl time.Duration) error {
+	return ns.c.Set(ns.prefix+key, value, ttl)
+}
+
+// Delete removes key from the namespace.
+func (ns *Namespace) Delete(key string) error {
+	return ns.c.Delete(ns.prefix + key)
+}
+
+// Clear removes every key in the namespace.
+func (ns *Namespace) Clear() error {
+	return ns.ClearPrefix("")
+}
+
+// ClearPrefix removes the keys in the namespace that start with prefix.
+func (ns *Namespace) ClearPrefix(prefix string) error {
+	pc, ok := ns.c.(PrefixClearer)
+	if !ok {
+		return fmt.Errorf("cache: clear namespace %q on %T: %w", ns.prefix+prefix, ns.c, errors.ErrUnsupported)
+	}
+	return pc.ClearPrefix(ns.prefix + prefix)
+}
+
+// GetInto decodes the value for key into dst, a non-nil pointer. Backends
+// implementing Decoder decode directly; for others the stored value must
+// be assignable to *dst or ErrTypeMismatch is returned.
+func (ns *Namespace) GetInto(key string, dst interface{}) error {
+	if d, ok := ns.c.(Decoder); ok {
+		return d.GetInto(ns.prefix+key, dst)
+	}
+
+	raw, err := ns.c.Get(ns.prefix + key)
+	if err != nil {
+		return err
+	}
+	return assign(dst, raw)
+}
+
+// GetMulti fetches keys from the namespace in one batch when the backend
+// supports it.
+func (ns *Namespace) GetMulti(keys []string) (map[string]interface{}, error) {
+	found, err := GetMulti(ns.c, ns.keys(keys))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{}, len(found))
+	for key, val := range found {
+		result[strings.TrimPrefix(key, ns.prefix)] = val
+	}
+	return result, nil
+}
+
+// SetMulti stores items in the namespace with the same TTL.
+func (ns *Namespace) SetMulti(items map[string]interface{}, ttl time.Duration) error {
+	prefixed := make(map[string]interface{}, len(items))
+	for key, val := range items {
+		prefixed[ns.prefix+key] = val
+	}
+	return SetMulti(ns.c, prefixed, ttl)
+}
+
+// DeleteMulti removes keys from the namespace. Missing keys are ignored.
+func (ns *Namespace) DeleteMulti(keys []string) error {
+	return DeleteMulti(ns.c, ns.keys(keys))
+}
+
+// GetCtx is like Get but honours ctx as described for WithContext.
+func (ns *Namespace) GetCtx(ctx context.Context, key string) (interface{}, error) {
+	return WithContext(ns.c).GetCtx(ctx, ns.prefix+key)
+}
+
+// SetCtx is like Set but honours ctx as described for WithContext.
+func (ns *Namespace) SetCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return WithContext(ns.c).SetCtx(ctx, ns.prefix+key, value, ttl)
+}
+
+// DeleteCtx is like Delete but honours ctx as described for WithContext.
+func (ns *Namespace) DeleteCtx(ctx context.Context, key string) error {
+	return WithContext(ns.c).DeleteCtx(ctx, ns.prefix+key)
+}
+
+// ClearCtx is like Clear but returns early if ctx is already done.
+func (ns *Namespace) ClearCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return ns.Clear()
+}
+
+// keys returns keys with the namespace prefix added
+func (ns *Namespace) keys(keys []string) []string {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = ns.prefix + key
+	}
+	return prefixed
+}
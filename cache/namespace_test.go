@@ -0,0 +1,107 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
+	"github.com/dhanalakshms/multi-backend-cache-go/inmemory"
+)
+
+// plainCache hides every optional interface of the cache it wraps
+type plainCache struct {
+	cache.Cache
+}
+
+// TestNamespace_Keys checks keys are prefixed and namespaces nest
+func TestNamespace_Keys(t *testing.T) {
+
+	backend := inmemory.NewLRUCache(0)
+	orders := cache.Namespaced(backend, "svc:orders:")
+	archive := cache.Namespaced(orders, "archive:")
+
+	if got := archive.Prefix(); got != "svc:orders:archive:" {
+		t.Fatalf("Expected nested prefix, got %q", got)
+	}
+
+	orders.Set("1", "live", 0)
+	archive.Set("1", "old", 0)
+
+	if val, err := backend.Get("svc:orders:1"); err != nil || val != "live" {
+		t.Fatalf("Expected live under svc:orders:1, got %v, %v", val, err)
+	}
+	if val, err := backend.Get("svc:orders:archive:1"); err != nil || val != "old" {
+		t.Fatalf("Expected old under svc:orders:archive:1, got %v, %v", val, err)
+	}
+	if _, err := cache.Namespaced(backend, "svc:users:").Get("1"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("Expected other namespace to miss, got %v", err)
+	}
+
+	if err := orders.Delete("1"); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := archive.Get("1"); err != nil || val != "old" {
+		t.Fatalf("Delete reached a nested namespace: %v, %v", val, err)
+	}
+
+	// batch results come back without the prefix
+	orders.SetMulti(map[string]interface{}{"a": 1, "b": 2}, time.Minute)
+	got, err := orders.GetMulti([]string{"a", "b", "c"})
+	if err != nil || len(got) != 2 || got["a"] != 1 {
+		t.Fatalf("Unexpected GetMulti result: %v, %v", got, err)
+	}
+
+	// typed reads work through the namespace
+	if n, err := cache.NewTyped[int](orders).Get("b"); err != nil || n != 2 {
+		t.Fatalf("Expected 2, got %v, %v", n, err)
+	}
+}
+
+// TestNamespace_Clear checks Clear stays inside the namespace
+func TestNamespace_Clear(t *testing.T) {
+
+	l1 := inmemory.NewLRUCache(0)
+	l2 := inmemory.NewLRUCache(0)
+	backend := cache.NewTiered(l1, l2, 0)
+
+	orders := cache.Namespaced(backend, "orders:")
+	users := cache.Namespaced(backend, "users:")
+	orders.Set("1", "o", 0)
+	users.Set("1", "u", 0)
+
+	if err := orders.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.Get("1"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("Expected orders to be cleared, got %v", err)
+	}
+	for _, tier := range []cache.Cache{l1, l2} {
+		if val, err := tier.Get("users:1"); err != nil || val != "u" {
+			t.Fatalf("Expected users untouched in every tier, got %v, %v", val, err)
+		}
+	}
+
+	// without prefix support Clear refuses instead of wiping everything
+	plain := cache.Namespaced(plainCache{l2}, "users:")
+	if err := plain.Clear(); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("Expected errors.ErrUnsupported, got %v", err)
+	}
+	if _, err := l2.Get("users:1"); err != nil {
+		t.Fatal("Unsupported Clear removed keys")
+	}
+	if err := cache.NewTiered(l1, plainCache{l2}, 0).ClearPrefix("users:"); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("Expected errors.ErrUnsupported from Tiered, got %v", err)
+	}
+}
+
+// TestNamespace_Contract runs the shared backend contract on a namespace
+func TestNamespace_Contract(t *testing.T) {
+	clock := cachetest.NewFakeClock(time.Now())
+	lru, err := inmemory.New[string, interface{}](inmemory.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cachetest.RunContractWithClock(t, cache.Namespaced(lru, "ns:"), clock)
+}
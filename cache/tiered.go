@@ -0,0 +1,210 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Tiered layers a fast local cache (L1), typically an inmemory.LRUCache,
+// over a shared remote cache (L2) such as Redis or Memcached.
+//
+// Reads try L1 first, fall through to L2 on a miss and backfill L1 when
+// an L1 TTL is set.
+// Writes go to L2 first and then L1; deletes and clears go to both.
+type Tiered struct {
+	l1    Cache
+	l2    Cache
+	l1TTL time.Duration
+}
+
+// NewTiered returns a Tiered cache over l1 and l2.
+// l1TTL caps how long an entry lives in L1, so L1 can expire before L2
+// and bound how stale a local copy gets. Values read from L2 are only
+// copied into L1 when l1TTL is positive: L2 does not report how long an
+// entry has left, so without a cap the copy could outlive it. Zero means
+// L1 uses the TTL passed to Set and reads do not backfill L1.
+func NewTiered(l1, l2 Cache, l1TTL time.Duration) *Tiered {
+	return &Tiered{
+		l1:    l1,
+		l2:    l2,
+		l1TTL: l1TTL,
+	}
+}
+
+// L1 returns the local tier.
+func (t *Tiered) L1() Cache { return t.l1 }
+
+// L2 returns the remote tier.
+func (t *Tiered) L2() Cache { return t.l2 }
+
+// Get returns the value from L1, or from L2 on an L1 miss.
+// Values found in L2 are copied into L1 if an L1 TTL is set.
+func (t *Tiered) Get(key string) (interface{}, error) {
+	if val, err := t.l1.Get(key); err == nil {
+		return val, nil
+	}
+
+	val, err := t.l2.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.l1TTL > 0 {
+		t.l1.Set(key, val, t.l1TTL)
+	}
+	return val, nil
+}
+
+// GetInto decodes the value for key into dst, a non-nil pointer, so typed
+// reads return the same Go type whether or not L1 held the key. L1 is used
+// when it holds a value assignable to *dst; otherwise the value is read
+// from L2, through its Decoder when it has one, and L1 is backfilled with
+// the typed value if an L1 TTL is set.
+func (t *Tiered) GetInto(key string, dst interface{}) error {
+	if val, err := t.l1.Get(key); err == nil {
+		if assign(dst, val) == nil {
+			return nil
+		}
+	}
+
+	if d, ok := t.l2.(Decoder); ok {
+		if err := d.GetInto(key, dst); err != nil {
+			return err
+		}
+	} else {
+		val, err := t.l2.Get(key)
+		if err != nil {
+			return err
+		}
+		if err := assign(dst, val); err != nil {
+			return err
+		}
+	}
+
+	if t.l1TTL > 0 {
+		t.l1.Set(key, reflect.ValueOf(dst).Elem().Interface(), t.l1TTL)
+	}
+	return nil
+}
+
+// Set stores value in L2 and then in L1.
+// If the L2 write fails, L1 is left without the key so it cannot serve
+// a value L2 never accepted.
+func (t *Tiered) Set(key string, value interface{}, ttl time.Duration) error {
+	if err := t.l2.Set(key, value, ttl); err != nil {
+		t.l1.Delete(key)
+		return err
+	}
+	return t.l1.Set(key, value, t.localTTL(ttl))
+}
+
+// Delete removes key from both tiers.
+// Returns ErrNotFound only if neither tier held the key.
+func (t *Tiered) Delete(key string) error {
+	err2 := t.l2.Delete(key)
+	err1 := t.l1.Delete(key)
+
+	if err2 != nil && !errors.Is(err2, ErrNotFound) {
+		return err2
+	}
+	if err1 != nil && !errors.Is(err1, ErrNotFound) {
+		return err1
+	}
+	if err1 != nil && err2 != nil {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Clear removes all entries from both tiers.
+func (t *Tiered) Clear() error {
+	err2 := t.l2.Clear()
+	err1 := t.l1.Clear()
+	if err2 != nil {
+		return err2
+	}
+	return err1
+}
+
+// ClearPrefix removes keys starting with prefix from both tiers. Both
+// tiers must implement PrefixClearer, otherwise nothing is removed and the
+// error matches errors.ErrUnsupported.
+func (t *Tiered) ClearPrefix(prefix string) error {
+	pc2, ok2 := t.l2.(PrefixClearer)
+	pc1, ok1 := t.l1.(PrefixClearer)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("cache: clear prefix %q on tiers %T and %T: %w", prefix, t.l1, t.l2, errors.ErrUnsupported)
+	}
+
+	err2 := pc2.ClearPrefix(prefix)
+	err1 := pc1.ClearPrefix(prefix)
+	if err2 != nil {
+		return err2
+	}
+	return err1
+}
+
+// GetMulti returns what L1 holds and fetches the rest from L2 in one batch,
+// copying those values into L1 if an L1 TTL is set.
+func (t *Tiered) GetMulti(keys []string) (map[string]interface{}, error) {
+	result, err := GetMulti(t.l1, keys)
+	if err != nil {
+		result = make(map[string]interface{}, len(keys))
+	}
+
+	var missing []string
+	for _, key := range keys {
+		if _, ok := result[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	remote, err := GetMulti(t.l2, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.l1TTL > 0 {
+		SetMulti(t.l1, remote, t.l1TTL)
+	}
+	for key, val := range remote {
+		result[key] = val
+	}
+	return result, nil
+}
+
+// SetMulti stores items in L2 and then in L1.
+func (t *Tiered) SetMulti(items map[string]interface{}, ttl time.Duration) error {
+	if err := SetMulti(t.l2, items, ttl); err != nil {
+		keys := make([]string, 0, len(items))
+		for key := range items {
+			keys = append(keys, key)
+		}
+		DeleteMulti(t.l1, keys)
+		return err
+	}
+	return SetMulti(t.l1, items, t.localTTL(ttl))
+}
+
+// DeleteMulti removes keys from both tiers. Missing keys are ignored.
+func (t *Tiered) DeleteMulti(keys []string) error {
+	err2 := DeleteMulti(t.l2, keys)
+	err1 := DeleteMulti(t.l1, keys)
+	if err2 != nil {
+		return err2
+	}
+	return err1
+}
+
+// localTTL is the shorter of ttl and the L1 cap, treating zero as no limit.
+func (t *Tiered) localTTL(ttl time.Duration) time.Duration {
+	if t.l1TTL > 0 && (ttl <= 0 || t.l1TTL < ttl) {
+		return t.l1TTL
+	}
+	return ttl
+}
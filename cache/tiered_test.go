@@ -0,0 +1,158 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
+	"github.com/dhanalakshms/multi-backend-cache-go/inmemory"
+)
+
+// TestTiered_ReadThroughAndBackfill checks L2 hits are copied into L1
+func TestTiered_ReadThroughAndBackfill(t *testing.T) {
+
+	l1 := inmemory.NewLRUCache(10)
+	l2 := inmemory.NewLRUCache(10)
+	tiered := cache.NewTiered(l1, l2, time.Minute)
+
+	// value only present in L2
+	l2.Set("k", "remote", 0)
+
+	val, err := tiered.Get("k")
+	if err != nil || val != "remote" {
+		t.Fatalf("Tiered Get failed: %v, %v", val, err)
+	}
+
+	if val, err := l1.Get("k"); err != nil || val != "remote" {
+		t.Fatal("L1 was not backfilled")
+	}
+
+	// missing from both tiers
+	if _, err := tiered.Get("missing"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+// TestTiered_WritesAndDeletes checks both tiers are kept in step
+func TestTiered_WritesAndDeletes(t *testing.T) {
+
+	l1 := inmemory.NewLRUCache(10)
+	l2 := inmemory.NewLRUCache(10)
+	tiered := cache.NewTiered(l1, l2, 0)
+
+	tiered.Set("k", "v", time.Minute)
+
+	if _, err := l1.Get("k"); err != nil {
+		t.Fatal("Set did not reach L1")
+	}
+	if _, err := l2.Get("k"); err != nil {
+		t.Fatal("Set did not reach L2")
+	}
+
+	// delete succeeds if only one tier has the key
+	l2.Delete("k")
+	if err := tiered.Delete("k"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := l1.Get("k"); err == nil {
+		t.Fatal("Delete did not reach L1")
+	}
+
+	tiered.Set("a", 1, 0)
+	tiered.Clear()
+
+	if _, err := l1.Get("a"); err == nil {
+		t.Fatal("Clear did not reach L1")
+	}
+	if _, err := l2.Get("a"); err == nil {
+		t.Fatal("Clear did not reach L2")
+	}
+}
+
+// TestTiered_ShorterL1TTL checks L1 expires before L2
+func TestTiered_ShorterL1TTL(t *testing.T) {
+
+	// both tiers follow the same fake clock
+	clock := cachetest.NewFakeClock(time.Now())
+	l1, _ := inmemory.New[string, interface{}](inmemory.WithCapacity(10), inmemory.WithClock(clock))
+	l2, _ := inmemory.New[string, interface{}](inmemory.WithCapacity(10), inmemory.WithClock(clock))
+	tiered := cache.NewTiered(l1, l2, 50*time.Millisecond)
+
+	tiered.Set("k", "v", time.Minute)
+	clock.Advance(100 * time.Millisecond)
+
+	if _, err := l1.Get("k"); err == nil {
+		t.Fatal("L1 entry should have expired")
+	}
+
+	// still served from L2 and backfilled again
+	if val, err := tiered.Get("k"); err != nil || val != "v" {
+		t.Fatalf("Expected L2 hit, got %v, %v", val, err)
+	}
+}
+
+// TestTiered_NoL1TTL checks reads do not backfill L1 without an L1 TTL,
+// so L1 never serves a key after L2 has expired it
+func TestTiered_NoL1TTL(t *testing.T) {
+
+	clock := cachetest.NewFakeClock(time.Now())
+	l1, _ := inmemory.New[string, interface{}](inmemory.WithCapacity(10), inmemory.WithClock(clock))
+	l2, _ := inmemory.New[string, interface{}](inmemory.WithCapacity(10), inmemory.WithClock(clock))
+	tiered := cache.NewTiered(l1, l2, 0)
+
+	l2.Set("k", "v", time.Minute)
+	l2.Set("m", "v", time.Minute)
+
+	if val, err := tiered.Get("k"); err != nil || val != "v" {
+		t.Fatalf("Expected L2 hit, got %v, %v", val, err)
+	}
+	var typed string
+	if err := tiered.GetInto("k", &typed); err != nil || typed != "v" {
+		t.Fatalf("Expected typed L2 hit, got %q, %v", typed, err)
+	}
+	if vals, err := tiered.GetMulti([]string{"m"}); err != nil || len(vals) != 1 {
+		t.Fatalf("Expected GetMulti L2 hit, got %v, %v", vals, err)
+	}
+	if l1.Len() != 0 {
+		t.Fatalf("Expected no backfill into L1, got %d entries", l1.Len())
+	}
+
+	clock.Advance(2 * time.Minute)
+
+	if _, err := tiered.Get("k"); err == nil {
+		t.Fatal("Expected a miss once L2 expired the key")
+	}
+	if vals, _ := tiered.GetMulti([]string{"m"}); len(vals) != 0 {
+		t.Fatalf("Expected GetMulti miss once L2 expired the key, got %v", vals)
+	}
+}
+
+// TestTiered_Batch checks GetMulti only asks L2 for L1 misses
+func TestTiered_Batch(t *testing.T) {
+
+	l1 := inmemory.NewLRUCache(10)
+	l2 := inmemory.NewLRUCache(10)
+	tiered := cache.NewTiered(l1, l2, time.Minute)
+
+	l1.Set("a", "A", 0)
+	l2.Set("b", "B", 0)
+
+	vals, err := tiered.GetMulti([]string{"a", "b", "c"})
+	if err != nil || len(vals) != 2 || vals["a"] != "A" || vals["b"] != "B" {
+		t.Fatalf("GetMulti failed: %v, %v", vals, err)
+	}
+
+	if _, err := l1.Get("b"); err != nil {
+		t.Fatal("GetMulti did not backfill L1")
+	}
+}
+
+// TestTiered_Contract checks the shared miss/delete contract
+func TestTiered_Contract(t *testing.T) {
+	clock := cachetest.NewFakeClock(time.Now())
+	l1, _ := inmemory.New[string, interface{}](inmemory.WithCapacity(10), inmemory.WithClock(clock))
+	l2, _ := inmemory.New[string, interface{}](inmemory.WithCapacity(10), inmemory.WithClock(clock))
+	cachetest.RunContractWithClock(t, cache.NewTiered(l1, l2, 0), clock)
+}
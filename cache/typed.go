@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTypeMismatch is returned by Typed.Get when the stored value
+// cannot be returned as the requested type.
+var ErrTypeMismatch = errors.New("cache: value has unexpected type")
+
+// Decoder is implemented by backends that store values in serialized form.
+// GetInto decodes the value for key directly into dst, which must be a
+// non-nil pointer, instead of into a generic interface{}.
+type Decoder interface {
+	GetInto(key string, dst interface{}) error
+}
+
+// Typed wraps a Cache so values are read back as V on every backend.
+type Typed[V any] struct {
+	c Cache
+}
+
+// NewTyped returns a Typed view over c.
+func NewTyped[V any](c Cache) *Typed[V] {
+	return &Typed[V]{c: c}
+}
+
+// Get retrieves the value for key as a V.
+// Backends implementing Decoder decode straight into V, others must
+// hold a value of type V or ErrTypeMismatch is returned.
+func (t *Typed[V]) Get(key string) (V, error) {
+	var v V
+
+	if d, ok := t.c.(Decoder); ok {
+		err := d.GetInto(key, &v)
+		return v, err
+	}
+
+	raw, err := t.c.Get(key)
+	if err != nil {
+		return v, err
+	}
+	if raw == nil {
+		return v, nil
+	}
+
+	v, ok := raw.(V)
+	if !ok {
+		return v, fmt.Errorf("%w: key %q holds %T, want %T", ErrTypeMismatch, key, raw, v)
+	}
+	return v, nil
+}
+
+// Set stores value for key with optional TTL.
+func (t *Typed[V]) Set(key string, value V, ttl time.Duration) error {
+	return t.c.Set(key, value, ttl)
+}
+
+// Delete removes key from the underlying cache.
+func (t *Typed[V]) Delete(key string) error {
+	return t.c.Delete(key)
+}
+
+// Clear removes all entries from the underlying cache.
+func (t *Typed[V]) Clear() error {
+	return t.c.Clear()
+}
@@ -0,0 +1,117 @@
+package inmemory
+
+// Segments an entry can live in, for policies with more than one list
+const (
+	segRecent   uint8 = iota // ARC T1, 2Q A1in: seen once recently
+	segFrequent              // ARC T2, 2Q Am: seen more than once
+)
+
+// arcPolicy implements the Adaptive Replacement Cache.
+//
+// t1 holds entries seen once recently, t2 entries seen at least twice.
+// b1 and b2 remember the keys recently evicted from t1 and t2. A miss on
+// a key in b1 means t1 was too small, a miss on a key in b2 means t2 was;
+// target, the preferred size of t1, moves accordingly.
+type arcPolicy[K comparable, V any] struct {
+	capacity int
+	target   int
+	t1, t2   nodeList[K, V]
+	b1, b2   *ghostList[K]
+
+	// ghost list the key passed to prepare was found in, if any
+	pending *ghostList[K]
+}
+
+func newARCPolicy[K comparable, V any](capacity int) *arcPolicy[K, V] {
+	p := &arcPolicy[K, V]{
+		capacity: capacity,
+		b1:       newGhostList[K](),
+		b2:       newGhostList[K](),
+	}
+	p.t1.init()
+	p.t2.init()
+	return p
+}
+
+func (p *arcPolicy[K, V]) prepare(key K) {
+	p.pending = nil
+
+	switch {
+	case p.b1.contains(key):
+		p.target = min(p.capacity, p.target+max(1, p.b2.len()/p.b1.len()))
+		p.b1.remove(key)
+		p.pending = p.b1
+	case p.b2.contains(key):
+		p.target = max(0, p.target-max(1, p.b1.len()/p.b2.len()))
+		p.b2.remove(key)
+		p.pending = p.b2
+	}
+}
+
+func (p *arcPolicy[K, V]) insert(n *Node[K, V]) {
+	// a key that was evicted recently has been seen twice
+	if p.pending != nil {
+		n.seg = segFrequent
+		p.t2.pushFront(n)
+	} else {
+		n.seg = segRecent
+		p.t1.pushFront(n)
+	}
+	p.pending = nil
+
+	// keep the ghost lists within the ARC directory size
+	for p.t1.len+p.b1.len() > p.capacity && p.b1.len() > 0 {
+		p.b1.removeBack()
+	}
+	for p.t1.len+p.t2.len+p.b1.len()+p.b2.len() > 2*p.capacity && p.b2.len() > 0 {
+		p.b2.removeBack()
+	}
+}
+
+func (p *arcPolicy[K, V]) access(n *Node[K, V]) {
+	if n.seg == segRecent {
+		p.t1.remove(n)
+		n.seg = segFrequent
+		p.t2.pushFront(n)
+		return
+	}
+	p.t2.moveToFront(n)
+}
+
+func (p *arcPolicy[K, V]) victim() *Node[K, V] {
+	if p.t1.len > 0 && (p.t1.len > p.target || (p.pending == p.b2 && p.t1.len == p.target) || p.t2.len == 0) {
+		return p.t1.back()
+	}
+	return p.t2.back()
+}
+
+func (p *arcPolicy[K, V]) evicted(n *Node[K, V]) {
+	p.removed(n)
+	if n.seg == segRecent {
+		p.b1.pushFront(n.key)
+	} else {
+		p.b2.pushFront(n.key)
+	}
+}
+
+func (p *arcPolicy[K, V]) removed(n *Node[K, V]) {
+	if n.seg == segRecent {
+		p.t1.remove(n)
+	} else {
+		p.t2.remove(n)
+	}
+}
+
+func (p *arcPolicy[K, V]) walk(fn func(n *Node[K, V])) {
+	p.t1.walk(fn)
+	p.t2.walk(fn)
+}
+
+func (p *arcPolicy[K, V]) reset() {
+	p.target = 0
+	p.t1.init()
+	p.t2.init()
+	p.b1.reset()
+	p.b2.reset()
+	p.pending = nil
+}
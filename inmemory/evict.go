@@ -0,0 +1,79 @@
+package inmemory
+
+import "fmt"
+
+// EvictReason tells an eviction callback why an entry left the cache.
+type EvictReason int
+
+const (
+	// EvictCapacity means the entry was evicted to make room.
+	EvictCapacity EvictReason = iota
+
+	// EvictExpired means the entry's TTL elapsed and it was removed by
+	// Get or by background cleanup.
+	EvictExpired
+
+	// EvictDeleted means the entry was removed with Delete or DeleteMulti.
+	EvictDeleted
+
+	// EvictCleared means the entry was removed by Clear.
+	EvictCleared
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictDeleted:
+		return "deleted"
+	case EvictCleared:
+		return "cleared"
+	}
+	return fmt.Sprintf("EvictReason(%d)", int(r))
+}
+
+// eviction is a removal waiting to be reported once the lock is released
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+// WithOnEvict sets a callback run for every entry that leaves the cache.
+// K and V must match the cache being built. See LRU.OnEvict.
+func WithOnEvict[K comparable, V any](fn func(key K, value V, reason EvictReason)) Option {
+	return func(c *config) {
+		c.onEvict = fn
+	}
+}
+
+// OnEvict sets a callback run for every entry that leaves the cache,
+// replacing any previous one; nil removes it. Overwriting a key with Set
+// is not a removal. Callbacks run after the cache lock is released, in the
+// goroutine whose call removed the entry, so they may use the cache.
+func (c *LRU[K, V]) OnEvict(fn func(key K, value V, reason EvictReason)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onEvict = fn
+}
+
+// notify queues node for the eviction callback; caller must hold c.mu
+func (c *LRU[K, V]) notify(node *Node[K, V], reason EvictReason) {
+	if c.onEvict != nil {
+		c.evictions = append(c.evictions, eviction[K, V]{node.key, node.value, reason})
+	}
+}
+
+// unlock releases c.mu, then reports the entries removed while it was held
+func (c *LRU[K, V]) unlock() {
+	fn, pending := c.onEvict, c.evictions
+	c.evictions = nil
+	c.mu.Unlock()
+
+	for _, e := range pending {
+		fn(e.key, e.value, e.reason)
+	}
+}
@@ -0,0 +1,107 @@
+package inmemory
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
+)
+
+// evictLog records eviction callbacks
+type evictLog struct {
+	mu      sync.Mutex
+	reasons map[string]EvictReason
+}
+
+func (l *evictLog) record(key string, _ interface{}, reason EvictReason) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.reasons == nil {
+		l.reasons = make(map[string]EvictReason)
+	}
+	l.reasons[key] = reason
+}
+
+func (l *evictLog) reason(key string) (EvictReason, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r, ok := l.reasons[key]
+	return r, ok
+}
+
+// TestOnEvict_Reasons checks each way an entry can leave is reported
+func TestOnEvict_Reasons(t *testing.T) {
+
+	var log evictLog
+	clock := cachetest.NewFakeClock(time.Now())
+	cache, err := New[string, interface{}](WithCapacity(2), WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.OnEvict(log.record)
+
+	cache.Set("a", 1, 0)
+	cache.Set("b", 2, 20*time.Millisecond)
+	cache.Set("c", 3, 0) // evicts a
+
+	// overwriting is not a removal
+	cache.Set("c", 4, 20*time.Millisecond)
+
+	clock.Advance(30 * time.Millisecond)
+	cache.Get("b")        // expired on read
+	cache.removeExpired() // c expired in cleanup
+	cache.Set("d", 5, 0)
+	cache.Delete("d")
+	cache.Set("e", 6, 0)
+	cache.Clear()
+
+	want := map[string]EvictReason{
+		"a": EvictCapacity,
+		"b": EvictExpired,
+		"c": EvictExpired,
+		"d": EvictDeleted,
+		"e": EvictCleared,
+	}
+	for key, reason := range want {
+		if got, ok := log.reason(key); !ok || got != reason {
+			t.Errorf("%s: expected %v, got %v (reported %v)", key, reason, got, ok)
+		}
+	}
+	if n := len(log.reasons); n != len(want) {
+		t.Errorf("Expected %d callbacks, got %d", len(want), n)
+	}
+}
+
+// TestOnEvict_RunsOutsideLock checks a callback can call back into the cache
+func TestOnEvict_RunsOutsideLock(t *testing.T) {
+
+	var evicted []int
+	var cache *LRU[string, int]
+
+	cache, err := New[string, int](
+		WithCapacity(1),
+		WithOnEvict(func(key string, value int, reason EvictReason) {
+			// would deadlock if called with the lock held
+			cache.Len()
+			evicted = append(evicted, value)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Set("a", 1, 0)
+	cache.Set("b", 2, 0)
+
+	if len(evicted) != 1 || evicted[0] != 1 {
+		t.Fatalf("Expected value 1 evicted, got %v", evicted)
+	}
+
+	if _, err := New[string, int](WithOnEvict(func(int, int, EvictReason) {})); !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("Expected ErrInvalidOption for mismatched callback, got %v", err)
+	}
+}
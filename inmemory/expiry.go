@@ -0,0 +1,64 @@
+package inmemory
+
+import "container/heap"
+
+// defaultCleanupBatch is how many expired entries one cleanup batch removes
+// before the lock is released
+const defaultCleanupBatch = 1024
+
+// expiryHeap orders entries with a TTL by deadline, soonest first.
+// It implements heap.Interface; each node tracks its own position so it
+// can be fixed or removed in O(log n).
+type expiryHeap[K comparable, V any] []*Node[K, V]
+
+func (h expiryHeap[K, V]) Len() int { return len(h) }
+
+func (h expiryHeap[K, V]) Less(i, j int) bool { return h[i].expiry.Before(h[j].expiry) }
+
+func (h expiryHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *expiryHeap[K, V]) Push(x any) {
+	n := x.(*Node[K, V])
+	n.heapIndex = len(*h)
+	*h = append(*h, n)
+}
+
+func (h *expiryHeap[K, V]) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	n.heapIndex = -1
+	return n
+}
+
+// track adds, moves or drops n after its expiry was set or changed
+func (h *expiryHeap[K, V]) track(n *Node[K, V]) {
+	switch {
+	case n.heapIndex >= 0 && n.expiry.IsZero():
+		heap.Remove(h, n.heapIndex)
+	case n.heapIndex >= 0:
+		heap.Fix(h, n.heapIndex)
+	case !n.expiry.IsZero():
+		heap.Push(h, n)
+	}
+}
+
+// untrack drops n if it has a TTL
+func (h *expiryHeap[K, V]) untrack(n *Node[K, V]) {
+	if n.heapIndex >= 0 {
+		heap.Remove(h, n.heapIndex)
+	}
+}
+
+// next returns the entry expiring soonest, or nil if none has a TTL
+func (h expiryHeap[K, V]) next() *Node[K, V] {
+	if len(h) == 0 {
+		return nil
+	}
+	return h[0]
+}
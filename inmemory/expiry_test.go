@@ -0,0 +1,105 @@
+package inmemory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
+)
+
+// checkHeap verifies the expiry heap order and node positions
+func checkHeap[K comparable, V any](t *testing.T, c *LRU[K, V]) {
+	t.Helper()
+
+	for i, n := range c.expiries {
+		if n.heapIndex != i {
+			t.Fatalf("Node at %d has index %d", i, n.heapIndex)
+		}
+		if parent := (i - 1) / 2; i > 0 && c.expiries[parent].expiry.After(n.expiry) {
+			t.Fatalf("Heap order broken at %d", i)
+		}
+	}
+}
+
+// TestExpiry_OnlyExpiredRemoved checks cleanup removes expired entries in batches
+func TestExpiry_OnlyExpiredRemoved(t *testing.T) {
+
+	clock := cachetest.NewFakeClock(time.Now())
+	cache, err := New[string, int](WithCleanupBatchSize(7), WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		cache.Set(fmt.Sprintf("short%d", i), i, 20*time.Millisecond)
+		cache.Set(fmt.Sprintf("long%d", i), i, time.Hour)
+		cache.Set(fmt.Sprintf("forever%d", i), i, 0)
+	}
+	checkHeap(t, cache)
+
+	if n := len(cache.expiries); n != 200 {
+		t.Fatalf("Expected 200 entries with a TTL, got %d", n)
+	}
+
+	clock.Advance(50 * time.Millisecond)
+
+	// a batch stops after its limit and reports the rest
+	if more := cache.removeExpiredBatch(); !more {
+		t.Fatal("Expected more expired entries after one batch")
+	}
+	if n := cache.Len(); n != 300-7 {
+		t.Fatalf("Expected one batch of 7 removed, got %d entries", n)
+	}
+
+	cache.removeExpired()
+	if n := cache.Len(); n != 200 {
+		t.Fatalf("Expected 200 entries left, got %d", n)
+	}
+	if _, err := cache.Get("long5"); err != nil {
+		t.Fatal("Expected long5 to stay")
+	}
+	checkHeap(t, cache)
+}
+
+// TestExpiry_UpdateAndDelete checks the heap follows TTL changes and removals
+func TestExpiry_UpdateAndDelete(t *testing.T) {
+
+	clock := cachetest.NewFakeClock(time.Now())
+	cache, err := New[string, int](WithCapacity(2), WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Set("a", 1, 20*time.Millisecond)
+	cache.Set("b", 2, time.Hour)
+
+	// dropping the TTL takes a out of the heap
+	cache.Set("a", 1, 0)
+	if a := cache.cache["a"]; a.heapIndex != -1 {
+		t.Fatalf("Expected a untracked, got index %d", a.heapIndex)
+	}
+
+	// shortening b's TTL moves it to the front
+	cache.Set("b", 2, 10*time.Millisecond)
+	checkHeap(t, cache)
+
+	clock.Advance(30 * time.Millisecond)
+	cache.removeExpired()
+
+	if _, err := cache.Get("a"); err != nil {
+		t.Fatal("Expected a without TTL to stay")
+	}
+	if _, err := cache.Get("b"); err == nil {
+		t.Fatal("Expected b to be removed")
+	}
+
+	// eviction and Delete take entries out of the heap as well
+	cache.Set("c", 3, time.Hour)
+	cache.Set("d", 4, time.Hour) // evicts a
+	cache.Set("e", 5, 0)         // evicts c
+	cache.Delete("d")
+	if n := len(cache.expiries); n != 0 {
+		t.Fatalf("Expected empty heap, got %d entries", n)
+	}
+}
@@ -0,0 +1,93 @@
+package inmemory
+
+import "sort"
+
+// lfuPolicy evicts the least frequently used entry in O(1).
+// Entries are bucketed by access count; each bucket is ordered by recency
+// so ties go to the least recently used entry.
+type lfuPolicy[K comparable, V any] struct {
+	buckets map[int]*nodeList[K, V]
+	minFreq int
+	count   int
+}
+
+func newLFUPolicy[K comparable, V any]() *lfuPolicy[K, V] {
+	return &lfuPolicy[K, V]{
+		buckets: make(map[int]*nodeList[K, V]),
+	}
+}
+
+// bucket returns the list for freq, creating it if needed
+func (p *lfuPolicy[K, V]) bucket(freq int) *nodeList[K, V] {
+	b, ok := p.buckets[freq]
+	if !ok {
+		b = &nodeList[K, V]{}
+		b.init()
+		p.buckets[freq] = b
+	}
+	return b
+}
+
+// unlink takes n out of its bucket and drops the bucket once empty
+func (p *lfuPolicy[K, V]) unlink(n *Node[K, V]) {
+	b := p.buckets[n.freq]
+	b.remove(n)
+	if b.len == 0 {
+		delete(p.buckets, n.freq)
+	}
+}
+
+func (p *lfuPolicy[K, V]) prepare(K) {}
+
+func (p *lfuPolicy[K, V]) insert(n *Node[K, V]) {
+	n.freq = 1
+	p.bucket(1).pushFront(n)
+	p.minFreq = 1
+	p.count++
+}
+
+func (p *lfuPolicy[K, V]) access(n *Node[K, V]) {
+	p.unlink(n)
+	if n.freq == p.minFreq && p.buckets[n.freq] == nil {
+		p.minFreq++
+	}
+	n.freq++
+	p.bucket(n.freq).pushFront(n)
+}
+
+func (p *lfuPolicy[K, V]) victim() *Node[K, V] {
+	if p.count == 0 {
+		return nil
+	}
+	// removals other than eviction can leave minFreq pointing at an
+	// empty bucket; walk up to the next populated one
+	for p.buckets[p.minFreq] == nil {
+		p.minFreq++
+	}
+	return p.buckets[p.minFreq].back()
+}
+
+func (p *lfuPolicy[K, V]) evicted(n *Node[K, V]) { p.removed(n) }
+
+func (p *lfuPolicy[K, V]) removed(n *Node[K, V]) {
+	p.unlink(n)
+	p.count--
+}
+
+func (p *lfuPolicy[K, V]) walk(fn func(n *Node[K, V])) {
+	freqs := make([]int, 0, len(p.buckets))
+	for freq := range p.buckets {
+		freqs = append(freqs, freq)
+	}
+	sort.Ints(freqs)
+
+	for _, freq := range freqs {
+		p.buckets[freq].walk(fn)
+	}
+}
+
+func (p *lfuPolicy[K, V]) reset() {
+	p.buckets = make(map[int]*nodeList[K, V])
+	p.minFreq = 0
+	p.count = 0
+}
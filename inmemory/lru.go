@@ -1,161 +1,471 @@
 package inmemory
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
 )
 
-// Node represents one entry in the cache 
-type Node struct {
-	key    string
-	value  interface{}
-	prev   *Node
-	next   *Node
+// Node represents one entry in the cache
+type Node[K comparable, V any] struct {
+	key    K
+	value  V
+	prev   *Node[K, V]
+	next   *Node[K, V]
 	expiry time.Time // expiry time for TTL
+	size   int64     // byte cost, tracked when a byte limit is set
+	freq   int       // access count, used by LFU
+	seg    uint8     // list the entry is in, used by ARC, 2Q and S3-FIFO
+
+	// hit marker set under the read lock, used by SIEVE and S3-FIFO
+	visited atomic.Uint32
+
+	heapIndex int // position in the expiry heap, -1 without a TTL
+
+	inWindow bool // whether the entry is in the TinyLFU admission window
 }
 
-// LRUCache stores cache data with LRU eviction policy
-type LRUCache struct {
+// ErrEntryTooLarge is returned by Set when a single entry is bigger than
+// the cache's byte limit.
+var ErrEntryTooLarge = errors.New("inmemory: entry larger than byte limit")
+
+// LRU stores typed cache data with LRU eviction policy.
+// Values are kept as-is, so Get returns exactly the type that was stored.
+// A different eviction policy can be chosen with WithPolicy.
+type LRU[K comparable, V any] struct {
 	capacity        int
-	cache           map[string]*Node 
-	head            *Node            
-	tail            *Node            
-	mu              sync.Mutex       
-	cleanupInterval time.Duration    
-	stopCleanup     chan struct{}   
+	cache           map[K]*Node[K, V]
+	policy          policy[K, V]
+	mu              sync.RWMutex
+	cleanupInterval time.Duration
+	cleanupBatch    int
+	stopCleanup     chan struct{}
+	expiries        expiryHeap[K, V] // entries with a TTL, soonest first
+	maxBytes        int64
+	bytes           int64
+	sizeOf          func(K, V) int64
+	sketch          *sketch[K] // access frequencies, set with AdmissionTinyLFU
+	sharedReads     bool       // hits only need the read lock
+	clock           cache.Clock
+	defaultTTL      time.Duration
+	onEvict         func(K, V, EvictReason)
+	evictions       []eviction[K, V] // removals to report on unlock
+
+	snapshotPath     string
+	snapshotInterval time.Duration
+	snapshotCodec    cache.Codec
+	stopSnapshots    chan struct{}
+	snapshotDone     chan struct{}
 }
 
-// NewLRUCache creates a new cache and optionally starts background cleanup
+// LRUCache is the string-keyed LRU that implements cache.Cache
+type LRUCache = LRU[string, interface{}]
+
+// NoTTL stores an entry without expiry, even when the cache has a
+// default TTL set with WithDefaultTTL.
+const NoTTL time.Duration = -1
+
+// NewLRUCache creates a new cache and optionally starts background cleanup.
+// For more settings, and errors for invalid values, use
+// NewLRUCacheWithOptions.
 func NewLRUCache(capacity int, cleanupInterval ...time.Duration) *LRUCache {
-	head := &Node{}
-	tail := &Node{}
-	head.next = tail
-	tail.prev = head
+	return NewLRU[string, interface{}](capacity, cleanupInterval...)
+}
 
-	c := &LRUCache{
-		capacity: capacity,
-		cache:    make(map[string]*Node),
-		head:     head,
-		tail:     tail,
+// NewLRU creates a new typed cache and optionally starts background cleanup
+func NewLRU[K comparable, V any](capacity int, cleanupInterval ...time.Duration) *LRU[K, V] {
+	cfg := config{capacity: capacity}
+	if len(cleanupInterval) > 0 {
+		cfg.cleanupInterval = cleanupInterval[0]
 	}
+	return newLRU[K, V](cfg, estimateSize[K, V], nil)
+}
+
+// newLRU builds a cache from already validated settings
+func newLRU[K comparable, V any](cfg config, sizeOf func(K, V) int64, onEvict func(K, V, EvictReason)) *LRU[K, V] {
+	c := &LRU[K, V]{
+		capacity:     cfg.capacity,
+		cache:        make(map[K]*Node[K, V]),
+		policy:       newPolicy[K, V](cfg.policy, cfg.capacity),
+		maxBytes:     cfg.maxBytes,
+		sizeOf:       sizeOf,
+		cleanupBatch: cfg.cleanupBatch,
+		onEvict:      onEvict,
+		clock:        cfg.clock,
+		defaultTTL:   cfg.defaultTTL,
+	}
+	if c.clock == nil {
+		c.clock = cache.SystemClock
+	}
+	if c.cleanupBatch == 0 {
+		c.cleanupBatch = defaultCleanupBatch
+	}
+
+	if cfg.admission == AdmissionTinyLFU {
+		c.sketch = newSketch[K](cfg.capacity)
+		c.policy = newTinyLFUPolicy[K, V](cfg.policy, cfg.capacity, c.sketch)
+	}
+	_, c.sharedReads = c.policy.(sharedAccess)
 
 	// start background cleanup if interval provided
-	if len(cleanupInterval) > 0 && cleanupInterval[0] > 0 {
-		c.cleanupInterval = cleanupInterval[0]
+	if cfg.cleanupInterval > 0 {
+		c.cleanupInterval = cfg.cleanupInterval
 		c.stopCleanup = make(chan struct{})
 		go c.startCleanup()
 	}
 
+	if cfg.snapshotInterval > 0 {
+		c.snapshotPath = cfg.snapshotPath
+		c.snapshotInterval = cfg.snapshotInterval
+		c.snapshotCodec = cfg.snapshotCodec
+		c.stopSnapshots = make(chan struct{})
+		c.snapshotDone = make(chan struct{})
+		go c.startSnapshots()
+	}
+
 	return c
 }
 
-// add inserts node right after head (mark as most recently used)
-func (c *LRUCache) add(node *Node) {
-	next := c.head.next
-	c.head.next = node
-	node.prev = c.head
-	node.next = next
-	next.prev = node
+// removeEntry drops node from the policy, the map and the byte count
+func (c *LRU[K, V]) removeEntry(node *Node[K, V], reason EvictReason) {
+	c.policy.removed(node)
+	c.forget(node)
+	c.notify(node, reason)
 }
 
-// remove disconnects a node from linked list
-func (c *LRUCache) remove(node *Node) {
-	node.prev.next = node.next
-	node.next.prev = node.prev
+// evict removes the policy's next victim, reporting false if there is none
+func (c *LRU[K, V]) evict() bool {
+	node := c.policy.victim()
+	if node == nil {
+		return false
+	}
+	c.policy.evicted(node)
+	c.forget(node)
+	c.notify(node, EvictCapacity)
+	return true
 }
 
-// Get returns value for a key and marks it as recently used
-func (c *LRUCache) Get(key string) (interface{}, error) {
+// forget drops node from the map, the expiry heap and the byte count
+func (c *LRU[K, V]) forget(node *Node[K, V]) {
+	delete(c.cache, node.key)
+	c.expiries.untrack(node)
+	c.bytes -= node.size
+}
+
+// Get returns value for a key and marks it as recently used.
+// Returns cache.ErrNotFound for a missing key and cache.ErrExpired
+// for a key whose TTL has elapsed.
+func (c *LRU[K, V]) Get(key K) (V, error) {
+	if c.sharedReads {
+		if val, ok, err := c.getShared(key); ok {
+			return val, err
+		}
+	}
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// getShared serves hits and misses under the read lock. It reports false
+// for an expired key, which needs the write lock to be removed.
+func (c *LRU[K, V]) getShared(key K) (V, bool, error) {
+	var zero V
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	node, ok := c.cache[key]
+	if !ok {
+		return zero, true, cache.ErrNotFound
+	}
+	if !node.expiry.IsZero() && c.clock.Now().After(node.expiry) {
+		return zero, false, nil
+	}
+
+	c.policy.access(node)
+	return node.value, true, nil
+}
+
+// get looks up key; caller must hold c.mu
+func (c *LRU[K, V]) get(key K) (V, error) {
+	var zero V
+
+	// misses count too: a key asked for often is worth admitting
+	if c.sketch != nil {
+		c.sketch.increment(key)
+	}
 
 	node, ok := c.cache[key]
 	if !ok {
-		return nil, fmt.Errorf("key not found")
+		return zero, cache.ErrNotFound
 	}
 
 	// check TTL expiration
-	if !node.expiry.IsZero() && time.Now().After(node.expiry) {
-		c.remove(node)
-		delete(c.cache, key)
-		return nil, fmt.Errorf("key expired")
+	if !node.expiry.IsZero() && c.clock.Now().After(node.expiry) {
+		c.removeEntry(node, EvictExpired)
+		return zero, cache.ErrExpired
 	}
 
-	// move node to front 
-	c.remove(node)
-	c.add(node)
+	c.policy.access(node)
 
 	return node.value, nil
 }
 
-// Set inserts or updates a key with optional TTL
-func (c *LRUCache) Set(key string, value interface{}, ttl time.Duration) error {
+// Set inserts or updates a key with optional TTL.
+// A TTL of 0 uses the default TTL (none unless set with WithDefaultTTL);
+// NoTTL or any negative TTL means no expiry.
+// Updating a key counts as an access to it. When the cache is full,
+// entries chosen by the eviction policy are removed until the new entry
+// fits; an entry bigger than the whole byte limit is rejected with
+// ErrEntryTooLarge and the cache is left unchanged.
+func (c *LRU[K, V]) Set(key K, value V, ttl time.Duration) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	// if key exists, remove old node
-	if node, ok := c.cache[key]; ok {
-		c.remove(node)
-		delete(c.cache, key)
-	}
+	return c.set(key, value, ttl)
+}
 
-	// evict least recently used if capacity reached
-	if c.capacity > 0 && len(c.cache) >= c.capacity {
-		lru := c.tail.prev
-		if lru != c.head {
-			c.remove(lru)
-			delete(c.cache, lru.key)
+// set inserts or updates key; caller must hold c.mu
+func (c *LRU[K, V]) set(key K, value V, ttl time.Duration) error {
+	var size int64
+	if c.maxBytes > 0 {
+		size = c.sizeOf(key, value)
+		if size > c.maxBytes {
+			return ErrEntryTooLarge
 		}
 	}
 
+	if c.sketch != nil {
+		c.sketch.increment(key)
+	}
+
+	if ttl == 0 {
+		ttl = c.defaultTTL
+	}
+
 	// calculate expiry time
 	expiry := time.Time{}
 	if ttl > 0 {
-		expiry = time.Now().Add(ttl)
+		expiry = c.clock.Now().Add(ttl)
 	}
 
-	node := &Node{
-		key:    key,
-		value:  value,
-		expiry: expiry,
+	// update an existing entry in place, keeping its place in the policy
+	if node, ok := c.cache[key]; ok {
+		c.bytes += size - node.size
+		node.value = value
+		node.expiry = expiry
+		node.size = size
+		c.expiries.track(node)
+		c.policy.access(node)
+
+		// a bigger value may push other entries out, but never this one
+		if c.maxBytes > 0 && c.bytes > c.maxBytes {
+			c.policy.removed(node)
+			for c.bytes > c.maxBytes {
+				if !c.evict() {
+					break
+				}
+			}
+			c.policy.prepare(key)
+			c.policy.insert(node)
+		}
+		return nil
 	}
 
-	// add new node as most recent
-	c.add(node)
-	c.cache[key] = node
+	c.policy.prepare(key)
+
+	// evict until the new entry fits
+	for c.overLimit(size) {
+		if !c.evict() {
+			break
+		}
+	}
+
+	node := &Node[K, V]{
+		key:       key,
+		value:     value,
+		expiry:    expiry,
+		size:      size,
+		heapIndex: -1,
+	}
 
+	c.expiries.track(node)
+	c.policy.insert(node)
+	c.cache[key] = node
+	c.bytes += size
 	return nil
 }
 
-// Delete removes a key manually
-func (c *LRUCache) Delete(key string) error {
+// overLimit reports whether adding an entry of size would exceed
+// the entry or byte limit
+func (c *LRU[K, V]) overLimit(size int64) bool {
+	if c.capacity > 0 && len(c.cache) >= c.capacity {
+		return true
+	}
+	return c.maxBytes > 0 && c.bytes+size > c.maxBytes
+}
+
+// Delete removes a key manually.
+// Returns cache.ErrNotFound if the key is not present.
+func (c *LRU[K, V]) Delete(key K) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
+
+	return c.delete(key)
+}
 
+// delete removes key; caller must hold c.mu
+func (c *LRU[K, V]) delete(key K) error {
 	node, ok := c.cache[key]
 	if !ok {
-		return fmt.Errorf("key not found")
+		return cache.ErrNotFound
 	}
 
-	c.remove(node)
-	delete(c.cache, key)
+	c.removeEntry(node, EvictDeleted)
+	return nil
+}
+
+// GetMulti returns the values for keys under a single lock acquisition.
+// Missing and expired keys are left out of the result.
+func (c *LRU[K, V]) GetMulti(keys []K) (map[K]V, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	result := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if val, err := c.get(key); err == nil {
+			result[key] = val
+		}
+	}
+	return result, nil
+}
+
+// SetMulti stores all items with the same TTL under a single lock acquisition.
+// Items that fit are stored even if another is rejected; the first
+// error is returned.
+func (c *LRU[K, V]) SetMulti(items map[K]V, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var firstErr error
+	for key, val := range items {
+		if err := c.set(key, val, ttl); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// DeleteMulti removes keys under a single lock acquisition.
+// Keys that are not present are ignored.
+func (c *LRU[K, V]) DeleteMulti(keys []K) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for _, key := range keys {
+		c.delete(key)
+	}
 	return nil
 }
 
 // Clear removes all entries and resets the list
-func (c *LRUCache) Clear() error {
+func (c *LRU[K, V]) Clear() error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.onEvict != nil {
+		for _, node := range c.cache {
+			c.notify(node, EvictCleared)
+		}
+	}
+
+	c.cache = make(map[K]*Node[K, V])
+	c.expiries = nil
+	c.policy.reset()
+	c.bytes = 0
+	return nil
+}
+
+// ClearPrefix removes every entry whose key starts with prefix, reporting
+// them to the eviction callback as EvictCleared. It needs string keys; on
+// other caches it returns an error matching errors.ErrUnsupported.
+func (c *LRU[K, V]) ClearPrefix(prefix string) error {
+	var zero K
+	if _, ok := any(zero).(string); !ok {
+		return fmt.Errorf("inmemory: clear prefix with %T keys: %w", zero, errors.ErrUnsupported)
+	}
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	c.cache = make(map[string]*Node)
-	c.head.next = c.tail
-	c.tail.prev = c.head
+	for key, node := range c.cache {
+		if strings.HasPrefix(any(key).(string), prefix) {
+			c.removeEntry(node, EvictCleared)
+		}
+	}
 	return nil
 }
 
+// Bytes returns the total size of all entries. It is only tracked when
+// the cache was built with WithMaxBytes and is zero otherwise.
+func (c *LRU[K, V]) Bytes() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.bytes
+}
+
+// Len returns the number of entries, including expired ones not yet removed
+func (c *LRU[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.cache)
+}
+
+// GetCtx is like Get but returns ctx's error if it is already done
+func (c *LRU[K, V]) GetCtx(ctx context.Context, key K) (V, error) {
+	if err := ctx.Err(); err != nil {
+		var zero V
+		return zero, err
+	}
+	return c.Get(key)
+}
+
+// SetCtx is like Set but returns ctx's error if it is already done
+func (c *LRU[K, V]) SetCtx(ctx context.Context, key K, value V, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.Set(key, value, ttl)
+}
+
+// DeleteCtx is like Delete but returns ctx's error if it is already done
+func (c *LRU[K, V]) DeleteCtx(ctx context.Context, key K) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.Delete(key)
+}
+
+// ClearCtx is like Clear but returns ctx's error if it is already done
+func (c *LRU[K, V]) ClearCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.Clear()
+}
+
 // startCleanup runs background worker to remove expired entries
-func (c *LRUCache) startCleanup() {
+func (c *LRU[K, V]) startCleanup() {
 	ticker := time.NewTicker(c.cleanupInterval)
 	defer ticker.Stop()
 
@@ -169,22 +479,35 @@ func (c *LRUCache) startCleanup() {
 	}
 }
 
-// removeExpired scans cache and removes expired keys
-func (c *LRUCache) removeExpired() {
+// removeExpired removes expired keys in batches of cleanupBatch,
+// releasing the lock between batches so requests are not stalled.
+// Only entries that have expired are visited.
+func (c *LRU[K, V]) removeExpired() {
+	for c.removeExpiredBatch() {
+	}
+}
+
+// removeExpiredBatch removes up to cleanupBatch expired entries and
+// reports whether more are left
+func (c *LRU[K, V]) removeExpiredBatch() bool {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	now := time.Now()
-	for key, node := range c.cache {
-		if !node.expiry.IsZero() && now.After(node.expiry) {
-			c.remove(node)
-			delete(c.cache, key)
+	now := c.clock.Now()
+	for i := 0; i < c.cleanupBatch; i++ {
+		node := c.expiries.next()
+		if node == nil || !now.After(node.expiry) {
+			return false
 		}
+		c.removeEntry(node, EvictExpired)
 	}
+
+	node := c.expiries.next()
+	return node != nil && now.After(node.expiry)
 }
 
 // StopCleanup stops the background cleanup goroutine
-func (c *LRUCache) StopCleanup() {
+func (c *LRU[K, V]) StopCleanup() {
 	if c.stopCleanup != nil {
 		close(c.stopCleanup)
 	}
@@ -150,3 +150,89 @@ func BenchmarkLRUAsyncMixedWithTTL(b *testing.B) {
 	cache.StopCleanup()
 }
 
+
+// concurrent Get on a single-lock LRU
+func BenchmarkLRUConcurrentGet(b *testing.B) {
+	cache := NewLRUCache(100000)
+
+	for i := 0; i < 100000; i++ {
+		cache.Set("key"+strconv.Itoa(i), "value", 0)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Get("key" + strconv.Itoa(i%100000))
+			i++
+		}
+	})
+}
+
+// concurrent Set on a sharded LRU
+func BenchmarkShardedLRUConcurrentSet(b *testing.B) {
+	cache := NewShardedLRUCache(32, 100000)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Set("key"+strconv.Itoa(i%100000), "value", 0)
+			i++
+		}
+	})
+}
+
+// concurrent Get on a sharded LRU
+func BenchmarkShardedLRUConcurrentGet(b *testing.B) {
+	cache := NewShardedLRUCache(32, 100000)
+
+	for i := 0; i < 100000; i++ {
+		cache.Set("key"+strconv.Itoa(i), "value", 0)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Get("key" + strconv.Itoa(i%100000))
+			i++
+		}
+	})
+}
+
+// concurrent mixed workload on a sharded LRU
+func BenchmarkShardedLRUMixedConcurrent(b *testing.B) {
+	cache := NewShardedLRUCache(32, 100000)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := "key" + strconv.Itoa(i%100000)
+
+			cache.Set(key, "value", 0)
+			cache.Get(key)
+
+			i++
+		}
+	})
+}
+
+// cleanup of a large cache where only a few entries have expired
+func BenchmarkLRURemoveExpiredFew(b *testing.B) {
+	cache := NewLRUCache(0)
+
+	for i := 0; i < 1000000; i++ {
+		cache.Set("key"+strconv.Itoa(i), "value", time.Hour)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		for j := 0; j < 100; j++ {
+			cache.Set("short"+strconv.Itoa(j), "value", time.Nanosecond)
+		}
+		b.StartTimer()
+
+		cache.removeExpired()
+	}
+}
@@ -1,20 +1,31 @@
 package inmemory
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
 	cacheasync "github.com/dhanalakshms/multi-backend-cache-go/cache"
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
 )
 
 // TestLRU_AllFunctionalities checks all basic behaviors of LRU cache
 func TestLRU_AllFunctionalities(t *testing.T) {
 
-	// Create cache with capacity 2 and background cleanup every 1 second
-	cache := NewLRUCache(2, 1*time.Second)
+	// Create cache with capacity 2, a fake clock and frequent background cleanup
+	clock := cachetest.NewFakeClock(time.Now())
+	cache, err := New[string, interface{}](
+		WithCapacity(2),
+		WithCleanupInterval(10*time.Millisecond),
+		WithClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Insert value and ensure it can be retrieved
-	err := cache.Set("t", "test1", 5*time.Second)
+	err = cache.Set("t", "test1", 5*time.Second)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -32,7 +43,7 @@ func TestLRU_AllFunctionalities(t *testing.T) {
 
 	// Key expires and is removed during Get
 	cache.Set("ttl", "value", 1*time.Second)
-	time.Sleep(2 * time.Second)
+	clock.Advance(2 * time.Second)
 
 	_, err = cache.Get("ttl")
 	if err == nil {
@@ -71,10 +82,15 @@ func TestLRU_AllFunctionalities(t *testing.T) {
 
 	// Expired key removed automatically by cleanup goroutine
 	cache.Set("bg", "value", 1*time.Second)
-	time.Sleep(3 * time.Second)
+	clock.Advance(2 * time.Second)
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Len() > 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
 
 	_, err = cache.Get("bg")
-	if err == nil {
+	if err == nil || cache.Len() != 1 {
 		t.Fatal("Background cleanup failed")
 	}
 
@@ -179,3 +195,270 @@ func TestLRU_AsyncConcurrentWrites(t *testing.T) {
 		}
 	}
 }
+
+// TestLRU_ContextOperations checks ctx variants and the generic adapter
+func TestLRU_ContextOperations(t *testing.T) {
+
+	cache := NewLRUCache(10)
+
+	var cc cacheasync.ContextCache = cache
+
+	if err := cc.SetCtx(context.Background(), "ctx", "value", 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	val, err := cc.GetCtx(context.Background(), "ctx")
+	if err != nil || val != "value" {
+		t.Fatal("GetCtx failed")
+	}
+
+	// Cancelled context must be reported before touching the cache
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := cc.SetCtx(ctx, "cancelled", "value", 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	if _, err := cache.Get("cancelled"); err == nil {
+		t.Fatal("Set with cancelled context should not store the key")
+	}
+
+	// Adapter around a plain cache.Cache behaves the same way
+	adapted := cacheasync.WithContext(struct{ cacheasync.Cache }{cache})
+
+	if _, err := adapted.GetCtx(ctx, "ctx"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled from adapter, got %v", err)
+	}
+
+	val, err = adapted.GetCtx(context.Background(), "ctx")
+	if err != nil || val != "value" {
+		t.Fatal("Adapter GetCtx failed")
+	}
+}
+
+// TestLRU_Contract checks the shared miss/delete contract
+func TestLRU_Contract(t *testing.T) {
+	clock := cachetest.NewFakeClock(time.Now())
+	cache, err := New[string, interface{}](WithCapacity(10), WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cachetest.RunContractWithClock(t, cache, clock)
+
+	// LRU can tell an expired key from a missing one
+	cache.Set("expired", "value", time.Millisecond)
+	clock.Advance(5 * time.Millisecond)
+
+	if _, err := cache.Get("expired"); !errors.Is(err, cacheasync.ErrExpired) {
+		t.Fatalf("Expected ErrExpired, got %v", err)
+	}
+}
+
+// TestLRU_Generic checks the typed LRU keeps key and value types
+func TestLRU_Generic(t *testing.T) {
+
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	cache := NewLRU[int, user](2)
+
+	cache.Set(1, user{"alice", 30}, 5*time.Second)
+	cache.Set(2, user{"bob", 40}, 5*time.Second)
+
+	u, err := cache.Get(1)
+	if err != nil || u.Name != "alice" || u.Age != 30 {
+		t.Fatalf("Typed Get failed: %+v, %v", u, err)
+	}
+
+	// key 2 is now least recently used
+	cache.Set(3, user{"carol", 50}, 5*time.Second)
+
+	if _, err := cache.Get(2); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected eviction of key 2, got %v", err)
+	}
+}
+
+// TestLRU_TypedWrapper checks cache.Typed over the in-memory backend
+func TestLRU_TypedWrapper(t *testing.T) {
+
+	typed := cacheasync.NewTyped[int](NewLRUCache(10))
+
+	typed.Set("n", 42, 5*time.Second)
+
+	n, err := typed.Get("n")
+	if err != nil || n != 42 {
+		t.Fatalf("Typed Get failed: %v, %v", n, err)
+	}
+
+	// value of another type stored through the plain interface
+	lru := NewLRUCache(10)
+	lru.Set("s", "text", 0)
+
+	if _, err := cacheasync.NewTyped[int](lru).Get("s"); !errors.Is(err, cacheasync.ErrTypeMismatch) {
+		t.Fatalf("Expected ErrTypeMismatch, got %v", err)
+	}
+}
+
+// TestLRU_BatchOperations checks native batch methods and the generic fallback
+func TestLRU_BatchOperations(t *testing.T) {
+
+	cache := NewLRUCache(10)
+
+	var bc cacheasync.BatchCache = cache
+
+	err := bc.SetMulti(map[string]interface{}{"a": 1, "b": 2, "c": 3}, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vals, err := bc.GetMulti([]string{"a", "b", "missing"})
+	if err != nil || len(vals) != 2 || vals["a"] != 1 || vals["b"] != 2 {
+		t.Fatalf("GetMulti failed: %v, %v", vals, err)
+	}
+
+	if err := bc.DeleteMulti([]string{"a", "missing"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cache.Get("a"); err == nil {
+		t.Fatal("DeleteMulti failed")
+	}
+
+	// Fallback loops over a plain cache.Cache
+	plain := struct{ cacheasync.Cache }{cache}
+
+	vals, err = cacheasync.GetMulti(plain, []string{"b", "c", "missing"})
+	if err != nil || len(vals) != 2 || vals["c"] != 3 {
+		t.Fatalf("Fallback GetMulti failed: %v, %v", vals, err)
+	}
+
+	if err := cacheasync.DeleteMulti(plain, []string{"b", "missing"}); err != nil {
+		t.Fatalf("Fallback DeleteMulti failed: %v", err)
+	}
+}
+
+// TestLRU_GetOrLoad checks concurrent misses share one loader call
+func TestLRU_GetOrLoad(t *testing.T) {
+
+	cache := NewLRUCache(10)
+
+	calls := 0
+	var mu sync.Mutex
+	loader := func() (interface{}, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		return "loaded", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := cacheasync.GetOrLoad(cache, "hot", 5*time.Second, loader)
+			if err != nil || val != "loaded" {
+				t.Errorf("GetOrLoad failed: %v, %v", val, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("Expected 1 loader call, got %d", calls)
+	}
+
+	if val, err := cache.Get("hot"); err != nil || val != "loaded" {
+		t.Fatal("Loaded value was not cached")
+	}
+
+	// Loader errors are returned but never cached
+	loadErr := errors.New("db down")
+	_, err := cacheasync.GetOrLoad(cache, "broken", 5*time.Second, func() (interface{}, error) {
+		return nil, loadErr
+	})
+	if !errors.Is(err, loadErr) {
+		t.Fatalf("Expected loader error, got %v", err)
+	}
+
+	if _, err := cache.Get("broken"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatal("Loader error should not be cached")
+	}
+}
+
+// TestLRU_TypedGetOrLoadJoinsUntyped checks a typed load that joins an
+// untyped one of another type reports the mismatch
+func TestLRU_TypedGetOrLoadJoinsUntyped(t *testing.T) {
+
+	cache := NewLRUCache(10)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cacheasync.GetOrLoad(cache, "shared", 0, func() (interface{}, error) {
+			close(started)
+			<-release
+			return "text", nil
+		})
+	}()
+	<-started
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	v, err := cacheasync.NewTyped[int](cache).GetOrLoad("shared", 0, func() (int, error) {
+		return 42, nil
+	})
+	<-done
+
+	if !errors.Is(err, cacheasync.ErrTypeMismatch) {
+		t.Fatalf("Expected ErrTypeMismatch, got %v, %v", v, err)
+	}
+}
+
+// TestLRU_ClearPrefix checks only matching keys are removed
+func TestLRU_ClearPrefix(t *testing.T) {
+
+	var cleared []string
+	cache, err := New[string, interface{}](WithOnEvict(func(key string, _ interface{}, reason EvictReason) {
+		if reason == EvictCleared {
+			cleared = append(cleared, key)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set("orders:1", 1, 0)
+	cache.Set("orders:2", 2, 0)
+	cache.Set("users:1", 3, 0)
+
+	if err := cache.ClearPrefix("orders:"); err != nil {
+		t.Fatal(err)
+	}
+	if n := cache.Len(); n != 1 {
+		t.Fatalf("Expected 1 entry left, got %d", n)
+	}
+	if len(cleared) != 2 {
+		t.Fatalf("Expected 2 EvictCleared callbacks, got %v", cleared)
+	}
+
+	sharded := NewShardedLRUCache(4, 0)
+	sharded.Set("orders:1", 1, 0)
+	sharded.Set("users:1", 2, 0)
+	sharded.ClearPrefix("orders:")
+	if _, err := sharded.Get("users:1"); err != nil || sharded.Len() != 1 {
+		t.Fatalf("Expected only users:1 left, got %d entries, %v", sharded.Len(), err)
+	}
+
+	// non-string keys cannot match a prefix
+	if err := NewLRU[int, int](0).ClearPrefix("1"); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("Expected errors.ErrUnsupported, got %v", err)
+	}
+}
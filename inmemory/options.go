@@ -0,0 +1,258 @@
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
+)
+
+// ErrInvalidOption is returned by New when an option value is rejected.
+var ErrInvalidOption = errors.New("inmemory: invalid option")
+
+// Option configures a cache built with New.
+type Option func(*config)
+
+// config collects option values before the cache is built
+type config struct {
+	capacity        int
+	cleanupInterval time.Duration
+	cleanupBatch    int
+	maxBytes        int64
+	sizeFunc        interface{} // func(K, V) int64, type-checked in New
+	policy          Policy
+	admission       Admission
+	onEvict         interface{} // func(K, V, EvictReason), type-checked in New
+	clock           cache.Clock
+	defaultTTL      time.Duration
+	shards          int
+
+	snapshotPath     string
+	snapshotInterval time.Duration
+	snapshotCodec    cache.Codec
+}
+
+// WithCapacity bounds the number of entries. Zero means unbounded.
+func WithCapacity(n int) Option {
+	return func(c *config) {
+		c.capacity = n
+	}
+}
+
+// WithDefaultTTL sets the TTL used when Set is called with a TTL of 0.
+// Pass NoTTL to Set to store an entry that never expires anyway.
+func WithDefaultTTL(d time.Duration) Option {
+	return func(c *config) {
+		c.defaultTTL = d
+	}
+}
+
+// WithShards sets the number of independently locked segments of a cache
+// built with NewShardedLRUCacheWithOptions. Defaults to 16. Capacity and
+// byte limits are split evenly between shards, so with WithMaxBytes the
+// largest entry accepted is one shard's share of the budget.
+func WithShards(n int) Option {
+	return func(c *config) {
+		c.shards = n
+	}
+}
+
+// WithCleanupInterval starts a background worker removing expired entries
+// every d. Zero disables background cleanup.
+func WithCleanupInterval(d time.Duration) Option {
+	return func(c *config) {
+		c.cleanupInterval = d
+	}
+}
+
+// WithCleanupBatchSize sets how many expired entries background cleanup
+// removes before releasing the lock for other requests. Defaults to 1024.
+func WithCleanupBatchSize(n int) Option {
+	return func(c *config) {
+		c.cleanupBatch = n
+	}
+}
+
+// WithClock sets the clock TTLs are measured against. Defaults to
+// cache.SystemClock; tests can pass a cachetest.FakeClock and advance it
+// by hand. Background cleanup still runs on a real ticker, but decides
+// what has expired by this clock.
+func WithClock(clock cache.Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// WithSnapshotFile saves a snapshot of the cache to path every interval,
+// encoding entries with codec, until StopSnapshots is called. Load it back
+// at startup with LoadFile.
+func WithSnapshotFile(path string, interval time.Duration, codec cache.Codec) Option {
+	return func(c *config) {
+		c.snapshotPath = path
+		c.snapshotInterval = interval
+		c.snapshotCodec = codec
+	}
+}
+
+// WithMaxBytes bounds the total size of all entries. Entries are evicted
+// until the cache is back under n bytes. Zero means no byte limit.
+// Sizes come from WithSizeFunc, or from an estimate: the length of
+// string and []byte keys and values, the shallow size of other types.
+func WithMaxBytes(n int64) Option {
+	return func(c *config) {
+		c.maxBytes = n
+	}
+}
+
+// WithSizeFunc sets how the size of an entry is measured for WithMaxBytes.
+// K and V must match the cache being built.
+func WithSizeFunc[K comparable, V any](fn func(key K, value V) int64) Option {
+	return func(c *config) {
+		c.sizeFunc = fn
+	}
+}
+
+// WithPolicy selects the eviction policy. Defaults to PolicyLRU.
+// PolicyARC, Policy2Q and PolicyS3FIFO size their internal queues from
+// the capacity, so they require WithCapacity.
+func WithPolicy(p Policy) Option {
+	return func(c *config) {
+		c.policy = p
+	}
+}
+
+// WithAdmission selects the admission policy. Defaults to AdmissionNone.
+// AdmissionTinyLFU sizes its window and frequency sketch from the
+// capacity, so it requires WithCapacity. It works with any Policy, which
+// then manages the entries outside the window.
+func WithAdmission(a Admission) Option {
+	return func(c *config) {
+		c.admission = a
+	}
+}
+
+// New creates a typed cache from options.
+// Invalid option values are reported as errors matching ErrInvalidOption.
+func New[K comparable, V any](opts ...Option) (*LRU[K, V], error) {
+	cfg, err := buildConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.shards > 0 {
+		return nil, fmt.Errorf("%w: shards are only supported by NewShardedLRUCacheWithOptions", ErrInvalidOption)
+	}
+
+	sizeOf, onEvict, err := resolveFuncs[K, V](cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newLRU[K, V](cfg, sizeOf, onEvict), nil
+}
+
+// NewLRUCacheWithOptions creates a string-keyed cache from options.
+// Unlike NewLRUCache, invalid values are reported as errors matching
+// ErrInvalidOption instead of being accepted silently.
+func NewLRUCacheWithOptions(opts ...Option) (*LRUCache, error) {
+	return New[string, interface{}](opts...)
+}
+
+// buildConfig applies opts and validates the result
+func buildConfig(opts []Option) (config, error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.capacity < 0 {
+		return cfg, fmt.Errorf("%w: negative capacity %d", ErrInvalidOption, cfg.capacity)
+	}
+	if cfg.cleanupInterval < 0 {
+		return cfg, fmt.Errorf("%w: negative cleanup interval %v", ErrInvalidOption, cfg.cleanupInterval)
+	}
+	if cfg.cleanupBatch < 0 {
+		return cfg, fmt.Errorf("%w: negative cleanup batch size %d", ErrInvalidOption, cfg.cleanupBatch)
+	}
+	if cfg.maxBytes < 0 {
+		return cfg, fmt.Errorf("%w: negative max bytes %d", ErrInvalidOption, cfg.maxBytes)
+	}
+	if cfg.defaultTTL < 0 {
+		return cfg, fmt.Errorf("%w: negative default TTL %v", ErrInvalidOption, cfg.defaultTTL)
+	}
+	if cfg.shards < 0 {
+		return cfg, fmt.Errorf("%w: negative shard count %d", ErrInvalidOption, cfg.shards)
+	}
+	if cfg.snapshotInterval < 0 {
+		return cfg, fmt.Errorf("%w: negative snapshot interval %v", ErrInvalidOption, cfg.snapshotInterval)
+	}
+	if cfg.snapshotInterval > 0 && (cfg.snapshotPath == "" || cfg.snapshotCodec == nil) {
+		return cfg, fmt.Errorf("%w: snapshots need a path and a codec", ErrInvalidOption)
+	}
+
+	switch cfg.policy {
+	case PolicyLRU, PolicyLFU, PolicySIEVE:
+	case PolicyARC, Policy2Q, PolicyS3FIFO:
+		if cfg.capacity == 0 {
+			return cfg, fmt.Errorf("%w: %v policy requires a capacity", ErrInvalidOption, cfg.policy)
+		}
+	default:
+		return cfg, fmt.Errorf("%w: unknown policy %v", ErrInvalidOption, cfg.policy)
+	}
+
+	switch cfg.admission {
+	case AdmissionNone:
+	case AdmissionTinyLFU:
+		if cfg.capacity == 0 {
+			return cfg, fmt.Errorf("%w: %v admission requires a capacity", ErrInvalidOption, cfg.admission)
+		}
+	default:
+		return cfg, fmt.Errorf("%w: unknown admission %v", ErrInvalidOption, cfg.admission)
+	}
+
+	return cfg, nil
+}
+
+// resolveFuncs type-checks the size func and eviction callback against K and V
+func resolveFuncs[K comparable, V any](cfg config) (func(K, V) int64, func(K, V, EvictReason), error) {
+	var key K
+	var value V
+
+	sizeOf := estimateSize[K, V]
+	if cfg.sizeFunc != nil {
+		fn, ok := cfg.sizeFunc.(func(K, V) int64)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: size func %T does not match cache of %T to %T", ErrInvalidOption, cfg.sizeFunc, key, value)
+		}
+		sizeOf = fn
+	}
+
+	var onEvict func(K, V, EvictReason)
+	if cfg.onEvict != nil {
+		fn, ok := cfg.onEvict.(func(K, V, EvictReason))
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: eviction callback %T does not match cache of %T to %T", ErrInvalidOption, cfg.onEvict, key, value)
+		}
+		onEvict = fn
+	}
+
+	return sizeOf, onEvict, nil
+}
+
+// estimateSize measures string and []byte data by length and any other
+// key or value by the shallow size of its type
+func estimateSize[K comparable, V any](key K, value V) int64 {
+	return shallowSize(key) + shallowSize(value)
+}
+
+func shallowSize(v interface{}) int64 {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(x))
+	case []byte:
+		return int64(len(x))
+	}
+	return int64(reflect.TypeOf(v).Size())
+}
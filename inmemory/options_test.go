@@ -0,0 +1,181 @@
+package inmemory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
+)
+
+// TestNew_RejectsInvalidOptions checks invalid values are reported
+func TestNew_RejectsInvalidOptions(t *testing.T) {
+
+	cases := map[string][]Option{
+		"negative capacity": {WithCapacity(-1)},
+		"negative interval": {WithCleanupInterval(-time.Second)},
+		"negative bytes":    {WithMaxBytes(-1)},
+		"size func types":   {WithSizeFunc(func(k int, v int) int64 { return 1 })},
+		"negative ttl":      {WithDefaultTTL(-time.Second)},
+		"shards on New":     {WithShards(4)},
+	}
+
+	for name, opts := range cases {
+		if _, err := New[string, interface{}](opts...); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%s: expected ErrInvalidOption, got %v", name, err)
+		}
+	}
+}
+
+// TestMaxBytes_EvictsUntilUnderBudget checks the byte limit with the default estimate
+func TestMaxBytes_EvictsUntilUnderBudget(t *testing.T) {
+
+	cache, err := New[string, interface{}](WithMaxBytes(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each entry costs 1 byte of key + 30 bytes of value
+	cache.Set("a", strings.Repeat("x", 30), 0)
+	cache.Set("b", strings.Repeat("x", 30), 0)
+	cache.Set("c", strings.Repeat("x", 30), 0)
+
+	if got := cache.Bytes(); got != 93 {
+		t.Fatalf("Expected 93 bytes, got %d", got)
+	}
+
+	// one large value forces out both a and b
+	cache.Set("d", strings.Repeat("x", 60), 0)
+
+	if _, err := cache.Get("a"); err == nil {
+		t.Fatal("Expected a to be evicted")
+	}
+	if _, err := cache.Get("b"); err == nil {
+		t.Fatal("Expected b to be evicted")
+	}
+	if got := cache.Bytes(); got > 100 {
+		t.Fatalf("Cache over budget: %d bytes", got)
+	}
+
+	// overwrite replaces the old cost
+	cache.Set("d", "y", 0)
+	if got := cache.Bytes(); got != 31+2 {
+		t.Fatalf("Expected 33 bytes after overwrite, got %d", got)
+	}
+
+	cache.Delete("d")
+	cache.Clear()
+	if got := cache.Bytes(); got != 0 {
+		t.Fatalf("Expected 0 bytes after Clear, got %d", got)
+	}
+}
+
+// TestMaxBytes_SizeFunc checks a user-supplied size function
+func TestMaxBytes_SizeFunc(t *testing.T) {
+
+	type blob struct{ data []byte }
+
+	cache, err := New[int, blob](
+		WithMaxBytes(10),
+		WithSizeFunc(func(_ int, b blob) int64 { return int64(len(b.data)) }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Set(1, blob{make([]byte, 4)}, 0)
+	cache.Set(2, blob{make([]byte, 4)}, 0)
+	cache.Set(3, blob{make([]byte, 4)}, 0)
+
+	if _, err := cache.Get(1); err == nil {
+		t.Fatal("Expected 1 to be evicted")
+	}
+	if got := cache.Bytes(); got != 8 {
+		t.Fatalf("Expected 8 bytes, got %d", got)
+	}
+
+	// bigger than the whole budget
+	if err := cache.Set(4, blob{make([]byte, 11)}, 0); !errors.Is(err, ErrEntryTooLarge) {
+		t.Fatalf("Expected ErrEntryTooLarge, got %v", err)
+	}
+	if _, err := cache.Get(2); err != nil {
+		t.Fatal("Rejected Set should leave the cache unchanged")
+	}
+}
+
+// TestNewLRUCacheWithOptions checks the options constructor and default TTL
+func TestNewLRUCacheWithOptions(t *testing.T) {
+
+	if _, err := NewLRUCacheWithOptions(WithCapacity(-1)); !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("Expected ErrInvalidOption for negative capacity, got %v", err)
+	}
+
+	clock := cachetest.NewFakeClock(time.Now())
+	evicted := 0
+	cache, err := NewLRUCacheWithOptions(
+		WithCapacity(2),
+		WithDefaultTTL(time.Minute),
+		WithClock(clock),
+		WithOnEvict(func(string, interface{}, EvictReason) { evicted++ }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Set("default", 1, 0)
+	cache.Set("forever", 2, NoTTL)
+	clock.Advance(2 * time.Minute)
+
+	if _, err := cache.Get("default"); err == nil {
+		t.Fatal("Expected default TTL to apply")
+	}
+	if _, err := cache.Get("forever"); err != nil {
+		t.Fatal("Expected NoTTL entry to stay")
+	}
+	if evicted != 1 {
+		t.Fatalf("Expected 1 eviction callback, got %d", evicted)
+	}
+}
+
+// TestNewShardedLRUCacheWithOptions checks options reach every shard
+func TestNewShardedLRUCacheWithOptions(t *testing.T) {
+
+	for name, opts := range map[string][]Option{
+		"negative shards": {WithShards(-1)},
+		"snapshots":       {WithSnapshotFile("x", time.Second, nil)},
+	} {
+		if _, err := NewShardedLRUCacheWithOptions(opts...); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%s: expected ErrInvalidOption, got %v", name, err)
+		}
+	}
+
+	clock := cachetest.NewFakeClock(time.Now())
+	cache, err := NewShardedLRUCacheWithOptions(
+		WithShards(4),
+		WithCapacity(100),
+		WithPolicy(PolicySIEVE),
+		WithDefaultTTL(time.Minute),
+		WithClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(cache.shards); n != 4 {
+		t.Fatalf("Expected 4 shards, got %d", n)
+	}
+	if c := cache.shards[0].capacity; c != 25 {
+		t.Fatalf("Expected 25 entries per shard, got %d", c)
+	}
+
+	cache.Set("k", "v", 0)
+	clock.Advance(2 * time.Minute)
+	if _, err := cache.Get("k"); err == nil {
+		t.Fatal("Expected default TTL to apply in shards")
+	}
+
+	if c, err := NewShardedLRUCacheWithOptions(); err != nil || len(c.shards) != defaultShards {
+		t.Fatalf("Expected %d default shards, got %v", defaultShards, err)
+	}
+}
@@ -0,0 +1,243 @@
+package inmemory
+
+import (
+	"container/list"
+	"fmt"
+)
+
+// Policy selects how the in-memory cache picks entries to evict.
+type Policy int
+
+const (
+	// PolicyLRU evicts the least recently used entry. This is the default.
+	PolicyLRU Policy = iota
+
+	// PolicyLFU evicts the least frequently used entry, breaking ties by recency.
+	PolicyLFU
+
+	// PolicyARC is the Adaptive Replacement Cache. It balances recency and
+	// frequency and remembers recently evicted keys to adapt that balance,
+	// which keeps one-off scans from flushing the hot set. Needs a capacity.
+	PolicyARC
+
+	// Policy2Q admits new keys into a small FIFO queue and only promotes
+	// them to the main LRU queue when they are seen again after leaving it,
+	// so single-use keys never displace the hot set. Needs a capacity.
+	Policy2Q
+
+	// PolicySIEVE keeps entries in insertion order and only marks them
+	// visited on a hit; eviction sweeps past visited entries, clearing the
+	// mark. Hits take a read lock only, so Get scales across goroutines.
+	PolicySIEVE
+
+	// PolicyS3FIFO filters new keys through a small FIFO queue and keeps
+	// those hit again in a main FIFO queue, giving each hit entry another
+	// pass before eviction. Like PolicySIEVE, hits take a read lock only.
+	// Needs a capacity.
+	PolicyS3FIFO
+)
+
+func (p Policy) String() string {
+	switch p {
+	case PolicyLRU:
+		return "LRU"
+	case PolicyLFU:
+		return "LFU"
+	case PolicyARC:
+		return "ARC"
+	case Policy2Q:
+		return "2Q"
+	case PolicySIEVE:
+		return "SIEVE"
+	case PolicyS3FIFO:
+		return "S3-FIFO"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// policy orders the entries of a cache for eviction.
+// The cache owns the map and the entries; the policy only links them into
+// its own lists. All methods are called with the cache lock held.
+type policy[K comparable, V any] interface {
+	// prepare is called before room is made for a key not in the cache
+	prepare(key K)
+
+	// insert places a newly added entry
+	insert(n *Node[K, V])
+
+	// access records a hit on an entry already in the cache
+	access(n *Node[K, V])
+
+	// victim returns the entry to evict next, or nil if there is none
+	victim() *Node[K, V]
+
+	// evicted unlinks an entry the cache removed to make room
+	evicted(n *Node[K, V])
+
+	// removed unlinks an entry that left for any other reason
+	removed(n *Node[K, V])
+
+	// reset forgets all entries
+	reset()
+
+	// walk visits every entry, roughly from the next to be evicted to the
+	// last, so inserting them in that order rebuilds a similar state
+	walk(fn func(n *Node[K, V]))
+}
+
+// sharedAccess is implemented by policies whose access only updates the
+// entry atomically and touches no list, so hits can be recorded while
+// holding just the cache's read lock.
+type sharedAccess interface {
+	sharedAccess()
+}
+
+// newPolicy builds the policy p for a cache holding up to capacity entries
+func newPolicy[K comparable, V any](p Policy, capacity int) policy[K, V] {
+	switch p {
+	case PolicyLFU:
+		return newLFUPolicy[K, V]()
+	case PolicyARC:
+		return newARCPolicy[K, V](capacity)
+	case Policy2Q:
+		return newTwoQPolicy[K, V](capacity)
+	case PolicySIEVE:
+		return newSIEVEPolicy[K, V]()
+	case PolicyS3FIFO:
+		return newS3FIFOPolicy[K, V](capacity)
+	}
+	return newLRUPolicy[K, V]()
+}
+
+// nodeList is an intrusive doubly linked list of cache entries with a
+// sentinel root: root.next is the front (most recent), root.prev the back
+type nodeList[K comparable, V any] struct {
+	root Node[K, V]
+	len  int
+}
+
+func (l *nodeList[K, V]) init() {
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	l.len = 0
+}
+
+// pushFront inserts n at the front
+func (l *nodeList[K, V]) pushFront(n *Node[K, V]) {
+	next := l.root.next
+	l.root.next = n
+	n.prev = &l.root
+	n.next = next
+	next.prev = n
+	l.len++
+}
+
+// remove disconnects n from the list
+func (l *nodeList[K, V]) remove(n *Node[K, V]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	n.prev = nil
+	n.next = nil
+	l.len--
+}
+
+// moveToFront marks n as most recent
+func (l *nodeList[K, V]) moveToFront(n *Node[K, V]) {
+	l.remove(n)
+	l.pushFront(n)
+}
+
+// walk visits entries from the back (oldest) to the front
+func (l *nodeList[K, V]) walk(fn func(n *Node[K, V])) {
+	for n := l.root.prev; n != &l.root; n = n.prev {
+		fn(n)
+	}
+}
+
+// back returns the oldest entry, or nil if the list is empty
+func (l *nodeList[K, V]) back() *Node[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// ghostList remembers recently evicted keys, most recent first
+type ghostList[K comparable] struct {
+	order *list.List
+	index map[K]*list.Element
+}
+
+func newGhostList[K comparable]() *ghostList[K] {
+	return &ghostList[K]{
+		order: list.New(),
+		index: make(map[K]*list.Element),
+	}
+}
+
+func (g *ghostList[K]) len() int { return g.order.Len() }
+
+func (g *ghostList[K]) contains(key K) bool {
+	_, ok := g.index[key]
+	return ok
+}
+
+// pushFront records key as most recently evicted
+func (g *ghostList[K]) pushFront(key K) {
+	if e, ok := g.index[key]; ok {
+		g.order.MoveToFront(e)
+		return
+	}
+	g.index[key] = g.order.PushFront(key)
+}
+
+// remove forgets key and reports whether it was present
+func (g *ghostList[K]) remove(key K) bool {
+	e, ok := g.index[key]
+	if !ok {
+		return false
+	}
+	g.order.Remove(e)
+	delete(g.index, key)
+	return true
+}
+
+// removeBack forgets the oldest key
+func (g *ghostList[K]) removeBack() {
+	if e := g.order.Back(); e != nil {
+		delete(g.index, e.Value.(K))
+		g.order.Remove(e)
+	}
+}
+
+func (g *ghostList[K]) reset() {
+	g.order.Init()
+	g.index = make(map[K]*list.Element)
+}
+
+// lruPolicy keeps entries in one list ordered by recency
+type lruPolicy[K comparable, V any] struct {
+	entries nodeList[K, V]
+}
+
+func newLRUPolicy[K comparable, V any]() *lruPolicy[K, V] {
+	p := &lruPolicy[K, V]{}
+	p.entries.init()
+	return p
+}
+
+func (p *lruPolicy[K, V]) prepare(K) {}
+
+func (p *lruPolicy[K, V]) insert(n *Node[K, V]) { p.entries.pushFront(n) }
+
+func (p *lruPolicy[K, V]) access(n *Node[K, V]) { p.entries.moveToFront(n) }
+
+func (p *lruPolicy[K, V]) victim() *Node[K, V] { return p.entries.back() }
+
+func (p *lruPolicy[K, V]) evicted(n *Node[K, V]) { p.entries.remove(n) }
+
+func (p *lruPolicy[K, V]) removed(n *Node[K, V]) { p.entries.remove(n) }
+
+func (p *lruPolicy[K, V]) reset() { p.entries.init() }
+
+func (p *lruPolicy[K, V]) walk(fn func(n *Node[K, V])) { p.entries.walk(fn) }
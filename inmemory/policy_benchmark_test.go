@@ -0,0 +1,88 @@
+package inmemory
+
+import (
+	"strconv"
+	"testing"
+)
+
+var benchPolicies = []Policy{PolicyLRU, PolicyLFU, PolicyARC, Policy2Q, PolicySIEVE, PolicyS3FIFO}
+
+// benchKeys turns a Zipf trace into string keys up front
+func benchKeys(n int) []string {
+	trace := zipfTrace(1, 1.1, 1000000, n)
+
+	keys := make([]string, n)
+	for i, k := range trace {
+		keys[i] = "key" + strconv.FormatUint(k, 10)
+	}
+	return keys
+}
+
+// hit ratio of each policy replaying a Zipf trace read-through
+func BenchmarkPolicyHitRatio(b *testing.B) {
+	keys := benchKeys(1 << 20)
+
+	for _, p := range benchPolicies {
+		b.Run(p.String(), func(b *testing.B) {
+			cache, _ := New[string, interface{}](WithCapacity(10000), WithPolicy(p))
+
+			hits := 0
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				key := keys[i%len(keys)]
+				if _, err := cache.Get(key); err == nil {
+					hits++
+					continue
+				}
+				cache.Set(key, "value", 0)
+			}
+			b.ReportMetric(100*float64(hits)/float64(b.N), "hit%")
+		})
+	}
+}
+
+// concurrent Get of Zipf-distributed keys, mostly hits
+func BenchmarkPolicyConcurrentGet(b *testing.B) {
+	keys := benchKeys(1 << 16)
+
+	for _, p := range benchPolicies {
+		b.Run(p.String(), func(b *testing.B) {
+			cache, _ := New[string, interface{}](WithCapacity(100000), WithPolicy(p))
+			for _, key := range keys {
+				cache.Set(key, "value", 0)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					cache.Get(keys[i%len(keys)])
+					i++
+				}
+			})
+		})
+	}
+}
+
+// concurrent read-through of Zipf-distributed keys: Get, Set on a miss
+func BenchmarkPolicyConcurrentReadThrough(b *testing.B) {
+	keys := benchKeys(1 << 20)
+
+	for _, p := range benchPolicies {
+		b.Run(p.String(), func(b *testing.B) {
+			cache, _ := New[string, interface{}](WithCapacity(10000), WithPolicy(p))
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					key := keys[i%len(keys)]
+					if _, err := cache.Get(key); err != nil {
+						cache.Set(key, "value", 0)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
+)
+
+// newPolicyCache builds a string cache with the given policy and capacity
+func newPolicyCache(t *testing.T, p Policy, capacity int, opts ...Option) *LRU[string, interface{}] {
+	t.Helper()
+
+	opts = append([]Option{WithCapacity(capacity), WithPolicy(p)}, opts...)
+	c, err := New[string, interface{}](opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// TestPolicy_Contract runs the shared cache contract against every policy
+func TestPolicy_Contract(t *testing.T) {
+
+	for _, p := range []Policy{PolicyLFU, PolicyARC, Policy2Q, PolicySIEVE, PolicyS3FIFO} {
+		clock := cachetest.NewFakeClock(time.Now())
+		t.Run(p.String(), func(t *testing.T) {
+			cachetest.RunContractWithClock(t, newPolicyCache(t, p, 10, WithClock(clock)), clock)
+		})
+	}
+}
+
+// TestPolicy_TTLAndCapacity checks every policy keeps TTL and capacity behavior
+func TestPolicy_TTLAndCapacity(t *testing.T) {
+
+	for _, p := range []Policy{PolicyLRU, PolicyLFU, PolicyARC, Policy2Q, PolicySIEVE, PolicyS3FIFO} {
+		clock := cachetest.NewFakeClock(time.Now())
+		c := newPolicyCache(t, p, 4, WithClock(clock))
+
+		c.Set("short", 1, 50*time.Millisecond)
+		for i := 0; i < 20; i++ {
+			c.Set(fmt.Sprintf("k%d", i), i, 0)
+			c.Get(fmt.Sprintf("k%d", i%3))
+		}
+
+		if n := c.Len(); n != 4 {
+			t.Errorf("%v: expected 4 entries, got %d", p, n)
+		}
+
+		c.Set("short", 1, 50*time.Millisecond)
+		clock.Advance(100 * time.Millisecond)
+		if _, err := c.Get("short"); err == nil {
+			t.Errorf("%v: expected short to expire", p)
+		}
+
+		c.Clear()
+		if n := c.Len(); n != 0 {
+			t.Errorf("%v: expected empty cache after Clear, got %d", p, n)
+		}
+		c.Set("again", 1, 0)
+		if _, err := c.Get("again"); err != nil {
+			t.Errorf("%v: expected Set after Clear to work, got %v", p, err)
+		}
+	}
+}
+
+// TestLFU_KeepsFrequentKey checks LFU evicts the least used key, not the oldest
+func TestLFU_KeepsFrequentKey(t *testing.T) {
+
+	c := newPolicyCache(t, PolicyLFU, 2)
+
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+
+	// a is older but used more often
+	c.Set("c", 3, 0)
+
+	if _, err := c.Get("a"); err != nil {
+		t.Fatal("Expected a to stay")
+	}
+	if _, err := c.Get("b"); err == nil {
+		t.Fatal("Expected b to be evicted")
+	}
+}
+
+// TestPolicy_ScanResistance checks a one-off scan does not flush the hot set
+func TestPolicy_ScanResistance(t *testing.T) {
+
+	const capacity = 100
+
+	hot := make([]string, capacity/2)
+	for i := range hot {
+		hot[i] = fmt.Sprintf("hot%d", i)
+	}
+
+	survivors := func(p Policy) int {
+		c := newPolicyCache(t, p, capacity)
+
+		// warm up: hot keys are read over and over amid light other traffic
+		for round := 0; round < 20; round++ {
+			for _, key := range hot {
+				if _, err := c.Get(key); err != nil {
+					c.Set(key, key, 0)
+				}
+			}
+			for i := 0; i < capacity/5; i++ {
+				c.Set(fmt.Sprintf("other%d-%d", round, i), i, 0)
+			}
+		}
+
+		// a scan touches many more keys than fit, each once
+		for i := 0; i < 10*capacity; i++ {
+			key := fmt.Sprintf("scan%d", i)
+			if _, err := c.Get(key); err != nil {
+				c.Set(key, key, 0)
+			}
+		}
+
+		n := 0
+		for _, key := range hot {
+			if _, err := c.Get(key); err == nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	if n := survivors(PolicyLRU); n != 0 {
+		t.Fatalf("Expected the scan to flush LRU, %d hot keys left", n)
+	}
+	for _, p := range []Policy{PolicyLFU, PolicyARC, Policy2Q, PolicyS3FIFO} {
+		if n := survivors(p); n < len(hot)*9/10 {
+			t.Errorf("%v: only %d of %d hot keys survived the scan", p, n, len(hot))
+		}
+	}
+}
+
+// TestSIEVE_KeepsVisitedKey checks a hit spares an entry from the next eviction
+func TestSIEVE_KeepsVisitedKey(t *testing.T) {
+
+	c := newPolicyCache(t, PolicySIEVE, 3)
+
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+	c.Set("c", 3, 0)
+	c.Get("a")
+
+	// a is oldest but visited, so the hand clears it and evicts b
+	c.Set("d", 4, 0)
+
+	if _, err := c.Get("a"); err != nil {
+		t.Fatal("Expected a to stay")
+	}
+	if _, err := c.Get("b"); err == nil {
+		t.Fatal("Expected b to be evicted")
+	}
+
+	// the hand resumes after b; c was never hit
+	c.Set("e", 5, 0)
+	if _, err := c.Get("c"); err == nil {
+		t.Fatal("Expected c to be evicted")
+	}
+}
+
+// TestPolicy_ConcurrentReads checks read-locked hits race safely with writes
+func TestPolicy_ConcurrentReads(t *testing.T) {
+
+	for _, p := range []Policy{PolicySIEVE, PolicyS3FIFO} {
+		c := newPolicyCache(t, p, 100)
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 2000; i++ {
+					key := fmt.Sprintf("k%d", (i*7+g)%150)
+					if g%4 == 0 {
+						c.Set(key, i, time.Millisecond)
+						continue
+					}
+					c.Get(key)
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		if n := c.Len(); n > 100 {
+			t.Errorf("%v: expected at most 100 entries, got %d", p, n)
+		}
+	}
+}
+
+// TestPolicy_RequiresCapacity checks ARC and 2Q reject an unbounded cache
+func TestPolicy_RequiresCapacity(t *testing.T) {
+
+	for _, p := range []Policy{PolicyARC, Policy2Q, PolicyS3FIFO} {
+		if _, err := New[string, int](WithPolicy(p)); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%v: expected ErrInvalidOption, got %v", p, err)
+		}
+	}
+
+	if _, err := New[string, int](WithPolicy(Policy(42))); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("unknown policy: expected ErrInvalidOption, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package inmemory
+
+// maxVisits caps the per-entry hit counter used by S3-FIFO
+const maxVisits = 3
+
+// s3fifoPolicy implements S3-FIFO.
+//
+// New keys enter small, a FIFO queue of a tenth of the capacity. An entry
+// leaving small is moved to main, a larger FIFO queue, if it was hit while
+// in small, and evicted otherwise; its key is then remembered in ghost,
+// and a key that comes back while in ghost goes straight to main. Main
+// gives every entry one more pass per hit (up to maxVisits) before
+// evicting it. A hit only bumps a counter and never moves an entry.
+type s3fifoPolicy[K comparable, V any] struct {
+	small, main nodeList[K, V]
+	ghost       *ghostList[K]
+	smallCap    int
+	ghostCap    int
+
+	// whether the key passed to prepare was found in ghost
+	fromGhost bool
+}
+
+func newS3FIFOPolicy[K comparable, V any](capacity int) *s3fifoPolicy[K, V] {
+	smallCap := max(1, capacity/10)
+
+	p := &s3fifoPolicy[K, V]{
+		ghost:    newGhostList[K](),
+		smallCap: smallCap,
+		ghostCap: max(1, capacity-smallCap),
+	}
+	p.small.init()
+	p.main.init()
+	return p
+}
+
+func (p *s3fifoPolicy[K, V]) sharedAccess() {}
+
+func (p *s3fifoPolicy[K, V]) prepare(key K) {
+	p.fromGhost = p.ghost.remove(key)
+}
+
+func (p *s3fifoPolicy[K, V]) insert(n *Node[K, V]) {
+	n.visited.Store(0)
+	if p.fromGhost {
+		n.seg = segFrequent
+		p.main.pushFront(n)
+	} else {
+		n.seg = segRecent
+		p.small.pushFront(n)
+	}
+	p.fromGhost = false
+}
+
+func (p *s3fifoPolicy[K, V]) access(n *Node[K, V]) {
+	for {
+		v := n.visited.Load()
+		if v >= maxVisits || n.visited.CompareAndSwap(v, v+1) {
+			return
+		}
+	}
+}
+
+func (p *s3fifoPolicy[K, V]) victim() *Node[K, V] {
+	for {
+		if p.small.len >= p.smallCap || p.main.len == 0 {
+			n := p.small.back()
+			if n == nil {
+				return nil
+			}
+			if n.visited.Load() == 0 {
+				return n
+			}
+			// hit while in small: move to main
+			p.small.remove(n)
+			n.visited.Store(0)
+			n.seg = segFrequent
+			p.main.pushFront(n)
+			continue
+		}
+
+		n := p.main.back()
+		if v := n.visited.Load(); v > 0 {
+			n.visited.Store(v - 1)
+			p.main.moveToFront(n)
+			continue
+		}
+		return n
+	}
+}
+
+func (p *s3fifoPolicy[K, V]) evicted(n *Node[K, V]) {
+	p.removed(n)
+	if n.seg == segRecent {
+		p.ghost.pushFront(n.key)
+		for p.ghost.len() > p.ghostCap {
+			p.ghost.removeBack()
+		}
+	}
+}
+
+func (p *s3fifoPolicy[K, V]) removed(n *Node[K, V]) {
+	if n.seg == segRecent {
+		p.small.remove(n)
+	} else {
+		p.main.remove(n)
+	}
+}
+
+func (p *s3fifoPolicy[K, V]) walk(fn func(n *Node[K, V])) {
+	p.small.walk(fn)
+	p.main.walk(fn)
+}
+
+func (p *s3fifoPolicy[K, V]) reset() {
+	p.small.init()
+	p.main.init()
+	p.ghost.reset()
+	p.fromGhost = false
+}
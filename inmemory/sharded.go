@@ -0,0 +1,270 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"time"
+)
+
+// ShardedLRUCache spreads keys over independently locked LRU segments,
+// so goroutines working on different keys rarely contend for a lock.
+// Eviction is per shard: each shard evicts its own least recently used
+// entry once it holds its share of the total capacity. A byte limit is
+// split the same way, so with WithMaxBytes an entry bigger than one
+// shard's share is rejected with ErrEntryTooLarge even if it would fit in
+// the total budget.
+type ShardedLRUCache struct {
+	shards      []*LRUCache
+	seed        maphash.Seed
+	stopCleanup chan struct{}
+}
+
+// defaultShards is the shard count of NewShardedLRUCacheWithOptions
+const defaultShards = 16
+
+// NewShardedLRUCache creates a cache of shards segments sharing capacity
+// between them, and optionally starts one background cleanup worker that
+// sweeps every shard. Capacity 0 means unbounded, as in NewLRUCache.
+// For more settings, and errors for invalid values, use
+// NewShardedLRUCacheWithOptions.
+func NewShardedLRUCache(shards, capacity int, cleanupInterval ...time.Duration) *ShardedLRUCache {
+	if shards < 1 {
+		shards = 1
+	}
+
+	cfg := config{capacity: capacity, shards: shards}
+	if len(cleanupInterval) > 0 {
+		cfg.cleanupInterval = cleanupInterval[0]
+	}
+	return newShardedLRUCache(cfg, estimateSize[string, interface{}], nil)
+}
+
+// NewShardedLRUCacheWithOptions creates a sharded cache from the same
+// options as New, plus WithShards. Every shard uses the configured policy,
+// admission, clock, default TTL and eviction callback. Periodic snapshots
+// are not supported. Invalid values are reported as errors matching
+// ErrInvalidOption.
+func NewShardedLRUCacheWithOptions(opts ...Option) (*ShardedLRUCache, error) {
+	cfg, err := buildConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.snapshotInterval > 0 {
+		return nil, fmt.Errorf("%w: snapshots are not supported by a sharded cache", ErrInvalidOption)
+	}
+	if cfg.shards == 0 {
+		cfg.shards = defaultShards
+	}
+
+	sizeOf, onEvict, err := resolveFuncs[string, interface{}](cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newShardedLRUCache(cfg, sizeOf, onEvict), nil
+}
+
+// newShardedLRUCache builds a sharded cache from already validated settings
+func newShardedLRUCache(cfg config, sizeOf func(string, interface{}) int64, onEvict func(string, interface{}, EvictReason)) *ShardedLRUCache {
+	shards := cfg.shards
+
+	// round up so the shards together hold at least capacity entries
+	shardCfg := cfg
+	if cfg.capacity > 0 {
+		shardCfg.capacity = (cfg.capacity + shards - 1) / shards
+	}
+	if cfg.maxBytes > 0 {
+		shardCfg.maxBytes = (cfg.maxBytes + int64(shards) - 1) / int64(shards)
+	}
+
+	// one worker below sweeps all shards
+	shardCfg.cleanupInterval = 0
+
+	c := &ShardedLRUCache{
+		shards: make([]*LRUCache, shards),
+		seed:   maphash.MakeSeed(),
+	}
+	for i := range c.shards {
+		c.shards[i] = newLRU[string, interface{}](shardCfg, sizeOf, onEvict)
+	}
+
+	if cfg.cleanupInterval > 0 {
+		c.stopCleanup = make(chan struct{})
+		go c.startCleanup(cfg.cleanupInterval)
+	}
+
+	return c
+}
+
+// shard returns the segment that owns key
+func (c *ShardedLRUCache) shard(key string) *LRUCache {
+	return c.shards[maphash.String(c.seed, key)%uint64(len(c.shards))]
+}
+
+// Get returns value for a key from its shard
+func (c *ShardedLRUCache) Get(key string) (interface{}, error) {
+	return c.shard(key).Get(key)
+}
+
+// Set inserts or updates a key in its shard
+func (c *ShardedLRUCache) Set(key string, value interface{}, ttl time.Duration) error {
+	return c.shard(key).Set(key, value, ttl)
+}
+
+// Delete removes a key from its shard.
+// Returns cache.ErrNotFound if the key is not present.
+func (c *ShardedLRUCache) Delete(key string) error {
+	return c.shard(key).Delete(key)
+}
+
+// Clear removes all entries from every shard
+func (c *ShardedLRUCache) Clear() error {
+	var firstErr error
+	for _, s := range c.shards {
+		if err := s.Clear(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ClearPrefix removes every entry whose key starts with prefix.
+func (c *ShardedLRUCache) ClearPrefix(prefix string) error {
+	var firstErr error
+	for _, s := range c.shards {
+		if err := s.ClearPrefix(prefix); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Len returns the number of entries across all shards
+func (c *ShardedLRUCache) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// Bytes returns the total size of the entries across all shards. Like
+// LRU.Bytes it is only tracked with WithMaxBytes and is zero otherwise.
+func (c *ShardedLRUCache) Bytes() int64 {
+	var n int64
+	for _, s := range c.shards {
+		n += s.Bytes()
+	}
+	return n
+}
+
+// OnEvict sets a callback run for every entry that leaves any shard.
+// See LRU.OnEvict.
+func (c *ShardedLRUCache) OnEvict(fn func(key string, value interface{}, reason EvictReason)) {
+	for _, s := range c.shards {
+		s.OnEvict(fn)
+	}
+}
+
+// GetCtx is like Get but returns ctx's error if it is already done
+func (c *ShardedLRUCache) GetCtx(ctx context.Context, key string) (interface{}, error) {
+	return c.shard(key).GetCtx(ctx, key)
+}
+
+// SetCtx is like Set but returns ctx's error if it is already done
+func (c *ShardedLRUCache) SetCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.shard(key).SetCtx(ctx, key, value, ttl)
+}
+
+// DeleteCtx is like Delete but returns ctx's error if it is already done
+func (c *ShardedLRUCache) DeleteCtx(ctx context.Context, key string) error {
+	return c.shard(key).DeleteCtx(ctx, key)
+}
+
+// ClearCtx is like Clear but returns ctx's error if it is already done
+func (c *ShardedLRUCache) ClearCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.Clear()
+}
+
+// GetMulti groups keys by shard and takes each shard's lock once.
+// Missing and expired keys are left out of the result.
+func (c *ShardedLRUCache) GetMulti(keys []string) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(keys))
+	for s, group := range c.groupKeys(keys) {
+		vals, _ := s.GetMulti(group)
+		for key, val := range vals {
+			result[key] = val
+		}
+	}
+	return result, nil
+}
+
+// SetMulti groups items by shard and takes each shard's lock once.
+// Every item is attempted; the first error is returned.
+func (c *ShardedLRUCache) SetMulti(items map[string]interface{}, ttl time.Duration) error {
+	groups := make(map[*LRUCache]map[string]interface{})
+	for key, val := range items {
+		s := c.shard(key)
+		if groups[s] == nil {
+			groups[s] = make(map[string]interface{})
+		}
+		groups[s][key] = val
+	}
+
+	var firstErr error
+	for s, group := range groups {
+		if err := s.SetMulti(group, ttl); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// DeleteMulti groups keys by shard and takes each shard's lock once.
+// Keys that are not present are ignored.
+func (c *ShardedLRUCache) DeleteMulti(keys []string) error {
+	var firstErr error
+	for s, group := range c.groupKeys(keys) {
+		if err := s.DeleteMulti(group); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// groupKeys splits keys by the shard that owns them
+func (c *ShardedLRUCache) groupKeys(keys []string) map[*LRUCache][]string {
+	groups := make(map[*LRUCache][]string)
+	for _, key := range keys {
+		s := c.shard(key)
+		groups[s] = append(groups[s], key)
+	}
+	return groups
+}
+
+// startCleanup sweeps expired entries from every shard, one shard at a time
+func (c *ShardedLRUCache) startCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, s := range c.shards {
+				s.removeExpired()
+			}
+		case <-c.stopCleanup:
+			return
+		}
+	}
+}
+
+// StopCleanup stops the background cleanup goroutine
+func (c *ShardedLRUCache) StopCleanup() {
+	if c.stopCleanup != nil {
+		close(c.stopCleanup)
+	}
+}
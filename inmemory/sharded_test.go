@@ -0,0 +1,165 @@
+package inmemory
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
+)
+
+// TestSharded_BasicOperations checks Get/Set/Delete/Clear across shards
+func TestSharded_BasicOperations(t *testing.T) {
+
+	cache := NewShardedLRUCache(8, 1000)
+
+	for i := 0; i < 100; i++ {
+		cache.Set("k"+strconv.Itoa(i), i, 5*time.Second)
+	}
+
+	for i := 0; i < 100; i++ {
+		val, err := cache.Get("k" + strconv.Itoa(i))
+		if err != nil || val != i {
+			t.Fatalf("Get k%d failed: %v, %v", i, val, err)
+		}
+	}
+
+	cache.Delete("k0")
+	if _, err := cache.Get("k0"); err == nil {
+		t.Fatal("Delete failed")
+	}
+
+	cache.Clear()
+	if _, err := cache.Get("k1"); err == nil {
+		t.Fatal("Clear failed")
+	}
+}
+
+// TestSharded_Capacity checks total entries stay within the shared capacity
+func TestSharded_Capacity(t *testing.T) {
+
+	cache := NewShardedLRUCache(4, 100)
+
+	for i := 0; i < 1000; i++ {
+		cache.Set("k"+strconv.Itoa(i), i, 0)
+	}
+
+	if total := cache.Len(); total > 100 {
+		t.Fatalf("Expected at most 100 entries, got %d", total)
+	}
+}
+
+// TestSharded_BackgroundCleanup checks expired keys are swept from every shard
+func TestSharded_BackgroundCleanup(t *testing.T) {
+
+	clock := cachetest.NewFakeClock(time.Now())
+	cache, err := NewShardedLRUCacheWithOptions(
+		WithShards(4),
+		WithCapacity(100),
+		WithCleanupInterval(5*time.Millisecond),
+		WithClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.StopCleanup()
+
+	for i := 0; i < 20; i++ {
+		cache.Set("k"+strconv.Itoa(i), i, time.Second)
+	}
+	clock.Advance(2 * time.Second)
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if n := cache.Len(); n != 0 {
+		t.Fatalf("Background cleanup left %d entries", n)
+	}
+}
+
+// TestSharded_Batch checks batch operations spanning shards
+func TestSharded_Batch(t *testing.T) {
+
+	cache := NewShardedLRUCache(4, 100)
+
+	cache.SetMulti(map[string]interface{}{"a": 1, "b": 2, "c": 3}, 0)
+
+	vals, err := cache.GetMulti([]string{"a", "b", "c", "missing"})
+	if err != nil || len(vals) != 3 {
+		t.Fatalf("GetMulti failed: %v, %v", vals, err)
+	}
+
+	cache.DeleteMulti([]string{"a", "b"})
+
+	vals, _ = cache.GetMulti([]string{"a", "b", "c"})
+	if len(vals) != 1 || vals["c"] != 3 {
+		t.Fatalf("DeleteMulti failed: %v", vals)
+	}
+}
+
+// TestSharded_MaxBytes checks byte usage is summed over shards and that
+// rejected entries are reported by the batch methods
+func TestSharded_MaxBytes(t *testing.T) {
+
+	cache, err := NewShardedLRUCacheWithOptions(WithShards(4), WithMaxBytes(400))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.SetMulti(map[string]interface{}{"a": "12345", "b": "123456789"}, 0)
+	if got := cache.Bytes(); got != 1+5+1+9 {
+		t.Fatalf("Expected 16 bytes in use, got %d", got)
+	}
+
+	// each shard holds a quarter of the budget
+	big := strings.Repeat("x", 150)
+	if err := cache.Set("big", big, 0); !errors.Is(err, ErrEntryTooLarge) {
+		t.Fatalf("Expected ErrEntryTooLarge from Set, got %v", err)
+	}
+	err = cache.SetMulti(map[string]interface{}{"c": "ok", "big": big}, 0)
+	if !errors.Is(err, ErrEntryTooLarge) {
+		t.Fatalf("Expected ErrEntryTooLarge from SetMulti, got %v", err)
+	}
+	if val, err := cache.Get("c"); err != nil || val != "ok" {
+		t.Fatalf("Expected the other items to be stored, got %v, %v", val, err)
+	}
+
+	cache.Clear()
+	if got := cache.Bytes(); got != 0 {
+		t.Fatalf("Expected 0 bytes after Clear, got %d", got)
+	}
+}
+
+// TestSharded_ConcurrentAccess checks thread safety
+func TestSharded_ConcurrentAccess(t *testing.T) {
+
+	cache := NewShardedLRUCache(16, 1000)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := "k" + strconv.Itoa(i)
+			cache.Set(key, i, 5*time.Second)
+			cache.Get(key)
+			cache.Delete(key)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestSharded_Contract checks the shared miss/delete contract
+func TestSharded_Contract(t *testing.T) {
+	clock := cachetest.NewFakeClock(time.Now())
+	cache, err := NewShardedLRUCacheWithOptions(WithShards(4), WithCapacity(100), WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cachetest.RunContractWithClock(t, cache, clock)
+}
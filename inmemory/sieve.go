@@ -0,0 +1,77 @@
+package inmemory
+
+// sievePolicy implements SIEVE.
+//
+// Entries sit in one FIFO queue and are never moved on a hit; a hit only
+// sets the entry's visited bit. To evict, a hand walks from the oldest
+// entry towards the newest, clearing visited bits, and evicts the first
+// entry found unvisited. The hand stays where it stopped for next time.
+type sievePolicy[K comparable, V any] struct {
+	entries nodeList[K, V]
+	hand    *Node[K, V]
+}
+
+func newSIEVEPolicy[K comparable, V any]() *sievePolicy[K, V] {
+	p := &sievePolicy[K, V]{}
+	p.entries.init()
+	return p
+}
+
+func (p *sievePolicy[K, V]) sharedAccess() {}
+
+func (p *sievePolicy[K, V]) prepare(K) {}
+
+func (p *sievePolicy[K, V]) insert(n *Node[K, V]) {
+	n.visited.Store(0)
+	p.entries.pushFront(n)
+}
+
+func (p *sievePolicy[K, V]) access(n *Node[K, V]) {
+	if n.visited.Load() == 0 {
+		n.visited.Store(1)
+	}
+}
+
+func (p *sievePolicy[K, V]) victim() *Node[K, V] {
+	if p.entries.len == 0 {
+		return nil
+	}
+
+	n := p.hand
+	if n == nil {
+		n = p.entries.back()
+	}
+	for n.visited.Load() != 0 {
+		n.visited.Store(0)
+		n = p.newer(n)
+	}
+	p.hand = n
+	return n
+}
+
+// newer returns the entry inserted after n, wrapping to the oldest
+func (p *sievePolicy[K, V]) newer(n *Node[K, V]) *Node[K, V] {
+	if n.prev == &p.entries.root {
+		return p.entries.back()
+	}
+	return n.prev
+}
+
+func (p *sievePolicy[K, V]) evicted(n *Node[K, V]) { p.removed(n) }
+
+func (p *sievePolicy[K, V]) removed(n *Node[K, V]) {
+	if p.hand == n {
+		p.hand = n.prev
+		if p.hand == &p.entries.root {
+			p.hand = nil
+		}
+	}
+	p.entries.remove(n)
+}
+
+func (p *sievePolicy[K, V]) walk(fn func(n *Node[K, V])) { p.entries.walk(fn) }
+
+func (p *sievePolicy[K, V]) reset() {
+	p.entries.init()
+	p.hand = nil
+}
@@ -0,0 +1,247 @@
+package inmemory
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
+)
+
+// snapshotMagic starts every snapshot and carries the format version
+const snapshotMagic = "LRUSNAP1"
+
+// maxSnapshotRecord bounds a single encoded key or value, so a corrupt
+// length cannot trigger a huge allocation
+const maxSnapshotRecord = 1 << 30
+
+// ErrBadSnapshot is returned by Restore when the data is not a snapshot
+// or is truncated.
+var ErrBadSnapshot = errors.New("inmemory: invalid snapshot")
+
+// snapshotEntry is one entry copied out of the cache for writing
+type snapshotEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	expiry time.Time
+}
+
+// Snapshot writes all live entries to w, encoding keys and values with
+// codec. Entries are written from the next to be evicted to the most
+// recently used, with their absolute expiry time, so Restore rebuilds
+// the same LRU order and time spent between Snapshot and Restore counts
+// against the TTL. Expired entries are skipped.
+// The lock is only held while entries are copied, not while writing.
+func (c *LRU[K, V]) Snapshot(w io.Writer, codec cache.Codec) error {
+	entries := c.liveEntries()
+
+	// write errors are sticky in bufio and surface from Flush
+	bw := bufio.NewWriter(w)
+	bw.WriteString(snapshotMagic)
+
+	for _, e := range entries {
+		key, err := codec.Marshal(e.key)
+		if err != nil {
+			return fmt.Errorf("inmemory: encode snapshot key %v: %w", e.key, err)
+		}
+		value, err := codec.Marshal(e.value)
+		if err != nil {
+			return fmt.Errorf("inmemory: encode snapshot value for key %v: %w", e.key, err)
+		}
+
+		var expiry int64
+		if !e.expiry.IsZero() {
+			expiry = e.expiry.UnixNano()
+		}
+
+		writeBytes(bw, key)
+		writeBytes(bw, value)
+		writeVarint(bw, expiry)
+	}
+
+	return bw.Flush()
+}
+
+// liveEntries copies the unexpired entries in eviction order
+func (c *LRU[K, V]) liveEntries() []snapshotEntry[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.clock.Now()
+	entries := make([]snapshotEntry[K, V], 0, len(c.cache))
+	c.policy.walk(func(n *Node[K, V]) {
+		if n.expiry.IsZero() || !now.After(n.expiry) {
+			entries = append(entries, snapshotEntry[K, V]{n.key, n.value, n.expiry})
+		}
+	})
+	return entries
+}
+
+// Restore reads a snapshot written by Snapshot and adds its entries to the
+// cache in their saved order, decoding with codec. Entries that expired in
+// the meantime are skipped; existing keys are overwritten. It returns the
+// number of entries restored. Nothing is added if the snapshot is invalid.
+func (c *LRU[K, V]) Restore(r io.Reader, codec cache.Codec) (int, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != snapshotMagic {
+		return 0, fmt.Errorf("%w: missing header", ErrBadSnapshot)
+	}
+
+	// decode everything first so a bad snapshot leaves the cache alone
+	var entries []snapshotEntry[K, V]
+	for {
+		keyData, err := readBytes(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		valueData, err := readBytes(br)
+		if err != nil {
+			return 0, err
+		}
+		expiry, err := binary.ReadVarint(br)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrBadSnapshot, err)
+		}
+
+		var e snapshotEntry[K, V]
+		if err := codec.Unmarshal(keyData, &e.key); err != nil {
+			return 0, fmt.Errorf("inmemory: decode snapshot key: %w", err)
+		}
+		if err := codec.Unmarshal(valueData, &e.value); err != nil {
+			return 0, fmt.Errorf("inmemory: decode snapshot value for key %v: %w", e.key, err)
+		}
+		if expiry != 0 {
+			e.expiry = time.Unix(0, expiry)
+		}
+		entries = append(entries, e)
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	restored := 0
+	for _, e := range entries {
+		ttl := NoTTL
+		if !e.expiry.IsZero() {
+			if ttl = e.expiry.Sub(now); ttl <= 0 {
+				continue
+			}
+		}
+		if err := c.set(e.key, e.value, ttl); err != nil {
+			continue
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// SaveFile writes a snapshot to path. The file is replaced atomically, so
+// a crash mid-write never leaves a truncated snapshot behind.
+func (c *LRU[K, V]) SaveFile(path string, codec cache.Codec) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := c.Snapshot(f, codec); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// LoadFile restores a snapshot from path. A missing file is reported with
+// an error matching fs.ErrNotExist, which callers starting without a
+// snapshot can ignore.
+func (c *LRU[K, V]) LoadFile(path string, codec cache.Codec) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return c.Restore(f, codec)
+}
+
+// startSnapshots saves a snapshot every snapshotInterval until StopSnapshots
+func (c *LRU[K, V]) startSnapshots() {
+	defer close(c.snapshotDone)
+
+	ticker := time.NewTicker(c.snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// a failed save is retried on the next tick
+			c.SaveFile(c.snapshotPath, c.snapshotCodec)
+		case <-c.stopSnapshots:
+			return
+		}
+	}
+}
+
+// StopSnapshots stops periodic snapshots started with WithSnapshotFile and
+// writes one last snapshot, returning its error. Call it on shutdown.
+func (c *LRU[K, V]) StopSnapshots() error {
+	if c.stopSnapshots == nil {
+		return nil
+	}
+	close(c.stopSnapshots)
+	<-c.snapshotDone
+	c.stopSnapshots = nil
+
+	return c.SaveFile(c.snapshotPath, c.snapshotCodec)
+}
+
+func writeVarint(w *bufio.Writer, v int64) {
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(buf[:binary.PutVarint(buf[:], v)])
+}
+
+// writeBytes writes b prefixed with its length
+func writeBytes(w *bufio.Writer, b []byte) {
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(buf[:binary.PutUvarint(buf[:], uint64(len(b)))])
+	w.Write(b)
+}
+
+// readBytes reads a length-prefixed record, returning io.EOF only when
+// the stream ends cleanly before it
+func readBytes(r *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadSnapshot, err)
+	}
+	if n > maxSnapshotRecord {
+		return nil, fmt.Errorf("%w: record of %d bytes", ErrBadSnapshot, n)
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadSnapshot, err)
+	}
+	return b, nil
+}
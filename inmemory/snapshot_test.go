@@ -0,0 +1,168 @@
+package inmemory
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cacheasync "github.com/dhanalakshms/multi-backend-cache-go/cache"
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
+)
+
+// TestSnapshot_RoundTrip checks values, TTLs and LRU order survive a restore
+func TestSnapshot_RoundTrip(t *testing.T) {
+
+	clock := cachetest.NewFakeClock(time.Now())
+	newCache := func(capacity int) *LRUCache {
+		c, err := New[string, interface{}](WithCapacity(capacity), WithClock(clock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	src := newCache(4)
+	src.Set("a", "A", 0)
+	src.Set("b", "B", 0)
+	src.Set("gone", "G", time.Second)
+	clock.Advance(2 * time.Second)
+	src.Set("c", "C", time.Minute)
+	src.Get("a") // order, oldest first: b, c, a
+
+	var buf bytes.Buffer
+	if err := src.Snapshot(&buf, cacheasync.JSONCodec{}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newCache(3)
+	n, err := dst.Restore(&buf, cacheasync.JSONCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("Expected 3 entries restored, got %d", n)
+	}
+	if _, err := dst.Get("gone"); err == nil {
+		t.Fatal("Expired entry should not be restored")
+	}
+
+	// b is still the least recently used
+	dst.Set("d", "D", 0)
+	if _, err := dst.Get("b"); err == nil {
+		t.Fatal("Expected b to be evicted first after restore")
+	}
+	if val, err := dst.Get("a"); err != nil || val != "A" {
+		t.Fatalf("Expected A, got %v, %v", val, err)
+	}
+
+	// c keeps its remaining TTL
+	clock.Advance(30 * time.Second)
+	if _, err := dst.Get("c"); err != nil {
+		t.Fatal("Expected c to still be live")
+	}
+	clock.Advance(31 * time.Second)
+	if _, err := dst.Get("c"); !errors.Is(err, cacheasync.ErrExpired) {
+		t.Fatalf("Expected c to expire, got %v", err)
+	}
+}
+
+// TestSnapshot_Typed checks a typed cache restores its value type
+func TestSnapshot_Typed(t *testing.T) {
+
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	src := NewLRU[int, user](0)
+	src.Set(1, user{"ann", 30}, 0)
+	src.Set(2, user{"bob", 40}, 0)
+
+	var buf bytes.Buffer
+	if err := src.Snapshot(&buf, cacheasync.JSONCodec{}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := NewLRU[int, user](0)
+	if _, err := dst.Restore(&buf, cacheasync.JSONCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	if u, err := dst.Get(2); err != nil || u.Name != "bob" || u.Age != 40 {
+		t.Fatalf("Expected bob, got %+v, %v", u, err)
+	}
+}
+
+// TestSnapshot_Invalid checks bad data is rejected without touching the cache
+func TestSnapshot_Invalid(t *testing.T) {
+
+	src := NewLRUCache(0)
+	src.Set("a", "A", 0)
+	src.Set("b", "B", 0)
+
+	var buf bytes.Buffer
+	src.Snapshot(&buf, cacheasync.JSONCodec{})
+	data := buf.Bytes()
+
+	dst := NewLRUCache(0)
+	cases := map[string][]byte{
+		"empty":     nil,
+		"no header": []byte("not a snapshot"),
+		"truncated": data[:len(data)-2],
+	}
+	for name, in := range cases {
+		if _, err := dst.Restore(bytes.NewReader(in), cacheasync.JSONCodec{}); !errors.Is(err, ErrBadSnapshot) {
+			t.Errorf("%s: expected ErrBadSnapshot, got %v", name, err)
+		}
+	}
+	if n := dst.Len(); n != 0 {
+		t.Fatalf("Expected nothing restored, got %d entries", n)
+	}
+}
+
+// TestSnapshot_Files checks saving and loading files, including periodic saves
+func TestSnapshot_Files(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "cache.snap")
+
+	empty := NewLRUCache(0)
+	if _, err := empty.LoadFile(path, cacheasync.JSONCodec{}); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected fs.ErrNotExist, got %v", err)
+	}
+
+	src, err := New[string, interface{}](WithSnapshotFile(path, 10*time.Millisecond, cacheasync.JSONCodec{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Set("a", "A", 0)
+
+	// wait for a background save
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("No periodic snapshot written")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// stopping writes a final snapshot including the latest write
+	src.Set("b", "B", 0)
+	if err := src.StopSnapshots(); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := NewLRUCache(0)
+	if n, err := dst.LoadFile(path, cacheasync.JSONCodec{}); err != nil || n != 2 {
+		t.Fatalf("Expected 2 entries loaded, got %d, %v", n, err)
+	}
+
+	if _, err := New[string, interface{}](WithSnapshotFile("", time.Second, cacheasync.JSONCodec{})); !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("Expected ErrInvalidOption without a path, got %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+package inmemory
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+)
+
+// Admission selects whether a new key may displace an existing entry.
+type Admission int
+
+const (
+	// AdmissionNone admits every key. This is the default.
+	AdmissionNone Admission = iota
+
+	// AdmissionTinyLFU puts new keys in a small LRU window (1% of the
+	// capacity) in front of the eviction policy. A key leaving the window
+	// only replaces the policy's victim if a frequency sketch has seen it
+	// more often; otherwise the key itself is dropped. This keeps
+	// one-hit-wonders from pushing out popular entries. Needs a capacity.
+	// Pairs best with PolicyLRU, PolicyLFU or PolicyARC; Policy2Q already
+	// filters new keys through its own queue and only loses hits.
+	AdmissionTinyLFU
+)
+
+func (a Admission) String() string {
+	switch a {
+	case AdmissionNone:
+		return "None"
+	case AdmissionTinyLFU:
+		return "TinyLFU"
+	}
+	return fmt.Sprintf("Admission(%d)", int(a))
+}
+
+// sketch depth and the largest value a 4-bit counter holds
+const (
+	sketchDepth = 4
+	counterMax  = 15
+)
+
+// sketch is a count-min sketch of 4-bit counters estimating how often
+// each key was seen recently. Once it has recorded about ten times as
+// many accesses as it has counters per row, all counters are halved, so
+// keys that were popular long ago fade out.
+type sketch[K comparable] struct {
+	seed    maphash.Seed
+	rows    [sketchDepth][]uint64 // 16 counters per word
+	mask    uint64                // counters per row - 1
+	added   int
+	resetAt int
+}
+
+// newSketch sizes the sketch for a cache of capacity entries
+func newSketch[K comparable](capacity int) *sketch[K] {
+	width := uint64(1) << bits.Len(uint(max(4*capacity, 16)-1))
+
+	s := &sketch[K]{
+		seed:    maphash.MakeSeed(),
+		mask:    width - 1,
+		resetAt: 10 * int(width),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint64, width/16)
+	}
+	return s
+}
+
+// index returns the counter position of hash h in row i
+func (s *sketch[K]) index(h uint64, i int) uint64 {
+	// double hashing: one 64-bit hash gives a position per row
+	lo, hi := h&0xffffffff, h>>32
+	return (lo + uint64(i)*hi) & s.mask
+}
+
+func (s *sketch[K]) counter(i int, idx uint64) uint64 {
+	return (s.rows[i][idx/16] >> ((idx % 16) * 4)) & 0xf
+}
+
+// increment records one access to key. Only the smallest counters are
+// raised, which keeps overestimates from hash collisions low.
+func (s *sketch[K]) increment(key K) {
+	h := hashKey(s.seed, key)
+
+	var idx [sketchDepth]uint64
+	least := uint64(counterMax)
+	for i := range idx {
+		idx[i] = s.index(h, i)
+		least = min(least, s.counter(i, idx[i]))
+	}
+	if least == counterMax {
+		return
+	}
+
+	for i := range idx {
+		if s.counter(i, idx[i]) == least {
+			s.rows[i][idx[i]/16] += 1 << ((idx[i] % 16) * 4)
+		}
+	}
+
+	s.added++
+	if s.added >= s.resetAt {
+		s.age()
+	}
+}
+
+// estimate returns how often key was seen recently
+func (s *sketch[K]) estimate(key K) uint64 {
+	h := hashKey(s.seed, key)
+
+	least := uint64(counterMax)
+	for i := 0; i < sketchDepth; i++ {
+		least = min(least, s.counter(i, s.index(h, i)))
+	}
+	return least
+}
+
+// age halves every counter
+func (s *sketch[K]) age() {
+	for i := range s.rows {
+		for j, w := range s.rows[i] {
+			s.rows[i][j] = (w >> 1) & 0x7777777777777777
+		}
+	}
+	s.added /= 2
+}
+
+func (s *sketch[K]) reset() {
+	for i := range s.rows {
+		clear(s.rows[i])
+	}
+	s.added = 0
+}
+
+// hashKey hashes strings and integers directly and anything else
+// through its printed form
+func hashKey[K comparable](seed maphash.Seed, key K) uint64 {
+	var b [8]byte
+
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(seed, k)
+	case int:
+		binary.LittleEndian.PutUint64(b[:], uint64(k))
+	case int64:
+		binary.LittleEndian.PutUint64(b[:], uint64(k))
+	case int32:
+		binary.LittleEndian.PutUint64(b[:], uint64(k))
+	case uint:
+		binary.LittleEndian.PutUint64(b[:], uint64(k))
+	case uint64:
+		binary.LittleEndian.PutUint64(b[:], k)
+	case uint32:
+		binary.LittleEndian.PutUint64(b[:], uint64(k))
+	default:
+		return maphash.String(seed, fmt.Sprint(key))
+	}
+	return maphash.Bytes(seed, b[:])
+}
+
+// tinyLFUPolicy implements W-TinyLFU on top of another policy.
+//
+// New entries go into window, a small LRU queue. Once the window is full,
+// its oldest entry is a candidate for the main policy: it is moved there
+// if the sketch rates it above the main policy's victim, which is then
+// evicted; otherwise the candidate itself is evicted.
+type tinyLFUPolicy[K comparable, V any] struct {
+	main      policy[K, V]
+	mainLen   int
+	mainCap   int
+	window    nodeList[K, V]
+	windowCap int
+	sketch    *sketch[K]
+
+	// window entry to move into main once the victim is evicted
+	admit *Node[K, V]
+}
+
+func newTinyLFUPolicy[K comparable, V any](p Policy, capacity int, s *sketch[K]) *tinyLFUPolicy[K, V] {
+	windowCap := max(1, capacity/100)
+
+	t := &tinyLFUPolicy[K, V]{
+		main:      newPolicy[K, V](p, max(1, capacity-windowCap)),
+		mainCap:   max(1, capacity-windowCap),
+		windowCap: windowCap,
+		sketch:    s,
+	}
+	t.window.init()
+	return t
+}
+
+func (p *tinyLFUPolicy[K, V]) prepare(K) {}
+
+func (p *tinyLFUPolicy[K, V]) insert(n *Node[K, V]) {
+	n.inWindow = true
+	p.window.pushFront(n)
+}
+
+func (p *tinyLFUPolicy[K, V]) access(n *Node[K, V]) {
+	if n.inWindow {
+		p.window.moveToFront(n)
+		return
+	}
+	p.main.access(n)
+}
+
+func (p *tinyLFUPolicy[K, V]) victim() *Node[K, V] {
+	p.admit = nil
+
+	// while main is filling up, window overflow moves in unchallenged
+	for p.window.len > p.windowCap && p.mainLen < p.mainCap {
+		p.promote(p.window.back())
+	}
+
+	// the window has room, so main is over its share
+	if p.window.len < p.windowCap {
+		if v := p.main.victim(); v != nil {
+			return v
+		}
+		return p.window.back()
+	}
+
+	candidate := p.window.back()
+	v := p.main.victim()
+	if v == nil {
+		return candidate
+	}
+	if p.sketch.estimate(candidate.key) > p.sketch.estimate(v.key) {
+		p.admit = candidate
+		return v
+	}
+	return candidate
+}
+
+func (p *tinyLFUPolicy[K, V]) evicted(n *Node[K, V]) {
+	if n.inWindow {
+		p.window.remove(n)
+		return
+	}
+	p.main.evicted(n)
+	p.mainLen--
+
+	if c := p.admit; c != nil {
+		p.admit = nil
+		p.promote(c)
+	}
+}
+
+// promote moves n from the window into main
+func (p *tinyLFUPolicy[K, V]) promote(n *Node[K, V]) {
+	p.window.remove(n)
+	n.inWindow = false
+	p.main.prepare(n.key)
+	p.main.insert(n)
+	p.mainLen++
+}
+
+func (p *tinyLFUPolicy[K, V]) removed(n *Node[K, V]) {
+	if n.inWindow {
+		p.window.remove(n)
+		return
+	}
+	p.main.removed(n)
+	p.mainLen--
+}
+
+func (p *tinyLFUPolicy[K, V]) walk(fn func(n *Node[K, V])) {
+	p.main.walk(fn)
+	p.window.walk(fn)
+}
+
+func (p *tinyLFUPolicy[K, V]) reset() {
+	p.main.reset()
+	p.mainLen = 0
+	p.window.init()
+	p.sketch.reset()
+	p.admit = nil
+}
@@ -0,0 +1,138 @@
+package inmemory
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// zipfTrace returns n keys drawn from a Zipf distribution over keys keys
+func zipfTrace(seed int64, s float64, keys uint64, n int) []uint64 {
+	z := rand.NewZipf(rand.New(rand.NewSource(seed)), s, 1, keys-1)
+
+	trace := make([]uint64, n)
+	for i := range trace {
+		trace[i] = z.Uint64()
+	}
+	return trace
+}
+
+// hitRatio replays trace read-through against a cache built from opts
+func hitRatio(t *testing.T, trace []uint64, opts ...Option) float64 {
+	t.Helper()
+
+	c, err := New[uint64, uint64](opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hits := 0
+	for _, key := range trace {
+		if _, err := c.Get(key); err == nil {
+			hits++
+			continue
+		}
+		c.Set(key, key, 0)
+	}
+	return float64(hits) / float64(len(trace))
+}
+
+// TestTinyLFU_ZipfHitRatio checks admission beats plain LRU on skewed traces
+func TestTinyLFU_ZipfHitRatio(t *testing.T) {
+
+	trace := zipfTrace(1, 1.1, 100000, 200000)
+
+	for _, p := range []Policy{PolicyLRU, PolicyLFU, PolicyARC, Policy2Q} {
+		plain := hitRatio(t, trace, WithCapacity(1000), WithPolicy(p))
+		admitted := hitRatio(t, trace, WithCapacity(1000), WithPolicy(p), WithAdmission(AdmissionTinyLFU))
+
+		t.Logf("%v: hit ratio %.3f, with TinyLFU %.3f", p, plain, admitted)
+		if p == PolicyLRU && admitted < plain+0.02 {
+			t.Errorf("Expected TinyLFU to improve LRU hit ratio, got %.3f vs %.3f", admitted, plain)
+		}
+		// 2Q already filters new keys through its own FIFO queue and gains
+		// nothing from admission on top of it
+		if p != Policy2Q && admitted < plain-0.01 {
+			t.Errorf("%v: TinyLFU hurt the hit ratio, got %.3f vs %.3f", p, admitted, plain)
+		}
+	}
+}
+
+// TestTinyLFU_OneHitWonders checks keys seen once do not flush a Zipf hot set
+func TestTinyLFU_OneHitWonders(t *testing.T) {
+
+	// each hot access is followed by three keys never seen again
+	hot := zipfTrace(2, 1.2, 10000, 100000)
+	trace := make([]uint64, 0, 4*len(hot))
+	for i, key := range hot {
+		once := uint64(1_000_000 + 3*i)
+		trace = append(trace, key, once, once+1, once+2)
+	}
+
+	plain := hitRatio(t, trace, WithCapacity(500))
+	admitted := hitRatio(t, trace, WithCapacity(500), WithAdmission(AdmissionTinyLFU))
+
+	t.Logf("hit ratio %.3f, with TinyLFU %.3f", plain, admitted)
+	if admitted < plain*1.1 {
+		t.Errorf("Expected TinyLFU to resist one-hit-wonders, got %.3f vs %.3f", admitted, plain)
+	}
+}
+
+// TestTinyLFU_KeepsCacheBehavior checks capacity, TTL and Clear with admission on
+func TestTinyLFU_KeepsCacheBehavior(t *testing.T) {
+
+	c, err := New[string, interface{}](WithCapacity(10), WithAdmission(AdmissionTinyLFU))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		c.Set(key, key, 0)
+	}
+	if n := c.Len(); n != 10 {
+		t.Fatalf("Expected 10 entries, got %d", n)
+	}
+
+	c.Clear()
+	if n := c.Len(); n != 0 {
+		t.Fatalf("Expected empty cache after Clear, got %d", n)
+	}
+
+	// a new key still gets in while there is room
+	c.Set("x", 1, 0)
+	if _, err := c.Get("x"); err != nil {
+		t.Fatalf("Expected x after Clear, got %v", err)
+	}
+
+	if _, err := New[string, int](WithAdmission(AdmissionTinyLFU)); !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("Expected ErrInvalidOption without capacity, got %v", err)
+	}
+}
+
+// TestSketch_EstimateAndAging checks counting, saturation and halving
+func TestSketch_EstimateAndAging(t *testing.T) {
+
+	s := newSketch[string](64)
+
+	for i := 0; i < 5; i++ {
+		s.increment("a")
+	}
+	for i := 0; i < 40; i++ {
+		s.increment("b")
+	}
+
+	if got := s.estimate("a"); got != 5 {
+		t.Fatalf("Expected estimate 5 for a, got %d", got)
+	}
+	if got := s.estimate("b"); got != counterMax {
+		t.Fatalf("Expected b to saturate at %d, got %d", counterMax, got)
+	}
+	if got := s.estimate("never"); got != 0 {
+		t.Fatalf("Expected 0 for an unseen key, got %d", got)
+	}
+
+	s.age()
+	if got := s.estimate("a"); got != 2 {
+		t.Fatalf("Expected a to halve to 2, got %d", got)
+	}
+}
@@ -0,0 +1,86 @@
+package inmemory
+
+// twoQPolicy implements the full 2Q algorithm.
+//
+// New keys enter a1in, a FIFO queue of about a quarter of the capacity.
+// Keys pushed out of a1in are remembered in the a1out ghost list; only a
+// key seen again while in a1out is promoted to am, the main LRU queue.
+// Entries hit while still in a1in are not promoted, which keeps bursts of
+// correlated accesses and one-off scans out of am.
+type twoQPolicy[K comparable, V any] struct {
+	kin, kout int
+	a1in, am  nodeList[K, V]
+	a1out     *ghostList[K]
+
+	// whether the key passed to prepare was found in a1out
+	promote bool
+}
+
+func newTwoQPolicy[K comparable, V any](capacity int) *twoQPolicy[K, V] {
+	p := &twoQPolicy[K, V]{
+		kin:   max(1, capacity/4),
+		kout:  max(1, capacity/2),
+		a1out: newGhostList[K](),
+	}
+	p.a1in.init()
+	p.am.init()
+	return p
+}
+
+func (p *twoQPolicy[K, V]) prepare(key K) {
+	p.promote = p.a1out.remove(key)
+}
+
+func (p *twoQPolicy[K, V]) insert(n *Node[K, V]) {
+	if p.promote {
+		n.seg = segFrequent
+		p.am.pushFront(n)
+	} else {
+		n.seg = segRecent
+		p.a1in.pushFront(n)
+	}
+	p.promote = false
+}
+
+func (p *twoQPolicy[K, V]) access(n *Node[K, V]) {
+	if n.seg == segFrequent {
+		p.am.moveToFront(n)
+	}
+}
+
+func (p *twoQPolicy[K, V]) victim() *Node[K, V] {
+	if p.a1in.len > p.kin || p.am.len == 0 {
+		return p.a1in.back()
+	}
+	return p.am.back()
+}
+
+func (p *twoQPolicy[K, V]) evicted(n *Node[K, V]) {
+	p.removed(n)
+	if n.seg == segRecent {
+		p.a1out.pushFront(n.key)
+		for p.a1out.len() > p.kout {
+			p.a1out.removeBack()
+		}
+	}
+}
+
+func (p *twoQPolicy[K, V]) removed(n *Node[K, V]) {
+	if n.seg == segRecent {
+		p.a1in.remove(n)
+	} else {
+		p.am.remove(n)
+	}
+}
+
+func (p *twoQPolicy[K, V]) walk(fn func(n *Node[K, V])) {
+	p.a1in.walk(fn)
+	p.am.walk(fn)
+}
+
+func (p *twoQPolicy[K, V]) reset() {
+	p.a1in.init()
+	p.am.init()
+	p.a1out.reset()
+	p.promote = false
+}
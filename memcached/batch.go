@@ -0,0 +1,150 @@
+package memcached
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// GetMulti fetches keys with a single multi-get per server, and the
+// chunks of all chunked values with one more.
+// Missing and expired keys are left out of the result.
+func (mc *MemcachedCache) GetMulti(keys []string) (map[string]interface{}, error) {
+	prefix, err := mc.keyPrefix()
+	if err != nil {
+		return nil, err
+	}
+
+	// item keys back to cache keys
+	keyOf := make(map[string]string, len(keys))
+	itemKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		k := storedKey(prefix + key)
+		if _, dup := keyOf[k]; !dup {
+			itemKeys = append(itemKeys, k)
+		}
+		keyOf[k] = key
+	}
+
+	items, err := mc.client.GetMulti(itemKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{}, len(items))
+	manifests := make(map[string]manifest)
+	var chunkKeys []string
+	for k, item := range items {
+		key := keyOf[k]
+		data, err := itemValue(prefix+key, item)
+		if err != nil {
+			continue // hashed key held by another key: a miss
+		}
+
+		if item.Flags&flagChunked != 0 {
+			m, err := parseManifest(data)
+			if err != nil {
+				continue
+			}
+			manifests[key] = m
+			chunkKeys = append(chunkKeys, m.chunkKeys(prefix+key)...)
+			continue
+		}
+
+		if err := mc.decodeMulti(key, data, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(manifests) == 0 {
+		return result, nil
+	}
+
+	chunks, err := mc.client.GetMulti(chunkKeys)
+	if err != nil {
+		return nil, err
+	}
+	for key, m := range manifests {
+		data, err := m.assemble(prefix+key, chunks)
+		if err != nil {
+			continue // missing or damaged chunk: a miss
+		}
+		if err := mc.decodeMulti(key, data, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// decodeMulti decodes data and adds it to result under key
+func (mc *MemcachedCache) decodeMulti(key string, data []byte, result map[string]interface{}) error {
+	var val interface{}
+	if err := mc.codec.Unmarshal(data, &val); err != nil {
+		return fmt.Errorf("memcached: decode value for key %q: %w", key, err)
+	}
+	result[key] = val
+	return nil
+}
+
+// SetMulti stores items with the same TTL.
+// The memcached protocol has no multi-set, so keys are written one by one
+// after all values have been encoded.
+func (mc *MemcachedCache) SetMulti(items map[string]interface{}, ttl time.Duration) error {
+	expiration := int32(0)
+	if ttl > 0 {
+		expiration = int32(ttl.Seconds())
+	}
+
+	prefix, err := mc.keyPrefix()
+	if err != nil {
+		return err
+	}
+
+	encoded := make([]*memcache.Item, 0, len(items))
+	keys := make([]string, 0, len(items))
+	for key, val := range items {
+		data, err := mc.codec.Marshal(val)
+		if err != nil {
+			return fmt.Errorf("memcached: encode value for key %q: %w", key, err)
+		}
+		keyItems, err := mc.newItems(prefix+key, data, expiration)
+		if err != nil {
+			return err
+		}
+		encoded = append(encoded, keyItems...)
+		keys = append(keys, prefix+key)
+	}
+
+	old := mc.oldChunks(keys...)
+	for _, item := range encoded {
+		if err := mc.client.Set(item); err != nil {
+			return err
+		}
+	}
+	mc.deleteChunks(old, encoded...)
+	return nil
+}
+
+// DeleteMulti removes keys one by one. Missing keys are ignored.
+func (mc *MemcachedCache) DeleteMulti(keys []string) error {
+	prefix, err := mc.keyPrefix()
+	if err != nil {
+		return err
+	}
+
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = prefix + key
+	}
+
+	old := mc.oldChunks(fullKeys...)
+	for _, key := range fullKeys {
+		if err := mc.client.Delete(storedKey(key)); err != nil && err != memcache.ErrCacheMiss {
+			return err
+		}
+	}
+	mc.deleteChunks(old)
+	return nil
+}
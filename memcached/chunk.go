@@ -0,0 +1,226 @@
+package memcached
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
+)
+
+// flagChunked marks a manifest item: the value is split across chunk
+// items and the item only describes them
+const flagChunked uint32 = 1 << 1
+
+// defaultMaxItemSize is memcached's default item size limit (-I 1m)
+const defaultMaxItemSize = 1 << 20
+
+// itemOverhead is the part of the item size limit kept free for the key
+// and the item header, so a full chunk still fits
+const itemOverhead = 1024
+
+// manifest describes a value stored in chunks. The nonce is fresh for
+// every write, so the chunks of an older write of the same key are never
+// mixed into a newer one.
+type manifest struct {
+	nonce  [8]byte
+	chunks int
+	size   int
+	digest [sha256.Size]byte
+}
+
+// chunkItems splits data for key into chunks of at most chunkSize bytes,
+// returning the chunk items, the manifest copy under chunksKey and the
+// manifest item, in the order they must be written
+func chunkItems(key string, data []byte, chunkSize int, expiration int32) ([]*memcache.Item, error) {
+	m := manifest{
+		chunks: (len(data) + chunkSize - 1) / chunkSize,
+		size:   len(data),
+		digest: sha256.Sum256(data),
+	}
+	if _, err := rand.Read(m.nonce[:]); err != nil {
+		return nil, err
+	}
+
+	items := make([]*memcache.Item, 0, m.chunks+1)
+	for i := 0; i < m.chunks; i++ {
+		chunk := data[i*chunkSize : min((i+1)*chunkSize, len(data))]
+		items = append(items, &memcache.Item{
+			Key:        m.chunkKey(key, i),
+			Value:      chunk,
+			Expiration: expiration,
+		})
+	}
+
+	items = append(items, newItem(chunksKey(key), m.marshal(), expiration))
+	manifestItem := newItem(key, m.marshal(), expiration)
+	manifestItem.Flags |= flagChunked
+	return append(items, manifestItem), nil
+}
+
+// chunksKey is the key of the copy of key's manifest that writes and
+// deletes look up to find old chunks. Unlike the item under key, it only
+// exists for chunked values, so looking it up never fetches a whole value.
+func chunksKey(key string) string {
+	return key + "#chunks"
+}
+
+// chunkKey returns the stored key of chunk i of key
+func (m manifest) chunkKey(key string, i int) string {
+	return storedKey(key + "#chunk:" + hex.EncodeToString(m.nonce[:]) + ":" + strconv.Itoa(i))
+}
+
+// chunkKeys returns the stored keys of every chunk of key
+func (m manifest) chunkKeys(key string) []string {
+	keys := make([]string, m.chunks)
+	for i := range keys {
+		keys[i] = m.chunkKey(key, i)
+	}
+	return keys
+}
+
+// assemble joins the chunks of key found in items. A missing chunk, or
+// chunks that do not match the manifest's size and digest, are a miss.
+func (m manifest) assemble(key string, items map[string]*memcache.Item) ([]byte, error) {
+	chunks := make([][]byte, m.chunks)
+	size := 0
+	for i := range chunks {
+		item, ok := items[m.chunkKey(key, i)]
+		if !ok {
+			return nil, cache.ErrNotFound
+		}
+		chunks[i] = item.Value
+		size += len(item.Value)
+	}
+	if size != m.size {
+		return nil, cache.ErrNotFound
+	}
+
+	data := bytes.Join(chunks, nil)
+	if sha256.Sum256(data) != m.digest {
+		return nil, cache.ErrNotFound
+	}
+	return data, nil
+}
+
+func (m manifest) marshal() []byte {
+	b := append([]byte(nil), m.nonce[:]...)
+	b = binary.AppendUvarint(b, uint64(m.chunks))
+	b = binary.AppendUvarint(b, uint64(m.size))
+	return append(b, m.digest[:]...)
+}
+
+// parseManifest decodes a manifest; an unreadable one is a miss
+func parseManifest(b []byte) (manifest, error) {
+	var m manifest
+	r := bytes.NewReader(b)
+
+	if _, err := io.ReadFull(r, m.nonce[:]); err != nil {
+		return m, cache.ErrNotFound
+	}
+	chunks, err := binary.ReadUvarint(r)
+	if err != nil {
+		return m, cache.ErrNotFound
+	}
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return m, cache.ErrNotFound
+	}
+	if _, err := io.ReadFull(r, m.digest[:]); err != nil || r.Len() != 0 {
+		return m, cache.ErrNotFound
+	}
+	// every chunk holds at least one byte
+	if chunks == 0 || chunks > size || size > math.MaxInt32 {
+		return m, cache.ErrNotFound
+	}
+
+	m.chunks, m.size = int(chunks), int(size)
+	return m, nil
+}
+
+// newItems builds the items storing data for key: one item when data,
+// with the original key stored in front of it for hashed keys, fits in a
+// single item, chunks and a manifest otherwise
+func (mc *MemcachedCache) newItems(key string, data []byte, expiration int32) ([]*memcache.Item, error) {
+	chunkSize := mc.maxItemSize - itemOverhead
+	if len(data) <= chunkSize-keyOverhead(key) {
+		return []*memcache.Item{newItem(key, data, expiration)}, nil
+	}
+	return chunkItems(key, data, chunkSize, expiration)
+}
+
+// oldChunks returns the stored keys of the chunks, and of the manifest
+// copy, of the chunked values currently stored under keys, so they can be
+// removed once the keys are overwritten or deleted instead of lingering
+// until memcached evicts them. Only the small manifest copies are read.
+// It is best effort: on any error no chunks are reported.
+func (mc *MemcachedCache) oldChunks(keys ...string) []string {
+	keyOf := make(map[string]string, len(keys))
+	for _, key := range keys {
+		keyOf[storedKey(chunksKey(key))] = key
+	}
+	stored := make([]string, 0, len(keyOf))
+	for k := range keyOf {
+		stored = append(stored, k)
+	}
+
+	items, err := mc.client.GetMulti(stored)
+	if err != nil {
+		return nil
+	}
+
+	var chunkKeys []string
+	for k, item := range items {
+		data, err := itemValue(chunksKey(keyOf[k]), item)
+		if err != nil {
+			continue
+		}
+		m, err := parseManifest(data)
+		if err != nil {
+			continue
+		}
+		chunkKeys = append(chunkKeys, m.chunkKeys(keyOf[k])...)
+		chunkKeys = append(chunkKeys, k)
+	}
+	return chunkKeys
+}
+
+// deleteChunks removes the items returned by oldChunks, ignoring errors.
+// Keys just rewritten by written, such as the manifest copy of a value
+// that is still chunked, are kept.
+func (mc *MemcachedCache) deleteChunks(chunkKeys []string, written ...*memcache.Item) {
+	keep := make(map[string]bool, len(written))
+	for _, item := range written {
+		keep[item.Key] = true
+	}
+	for _, k := range chunkKeys {
+		if !keep[k] {
+			mc.client.Delete(k)
+		}
+	}
+}
+
+// value returns the encoded value item holds for key, fetching and
+// reassembling the chunks when item is a manifest
+func (mc *MemcachedCache) value(key string, item *memcache.Item) ([]byte, error) {
+	data, err := itemValue(key, item)
+	if err != nil || item.Flags&flagChunked == 0 {
+		return data, err
+	}
+
+	m, err := parseManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := mc.client.GetMulti(m.chunkKeys(key))
+	if err != nil {
+		return nil, err
+	}
+	return m.assemble(key, chunks)
+}
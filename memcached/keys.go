@@ -0,0 +1,108 @@
+package memcached
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
+)
+
+// maxKeyLength is the longest key memcached accepts
+const maxKeyLength = 250
+
+// flagHashedKey marks an item stored under a hashed key. Its value starts
+// with the original key, length-prefixed, followed by the encoded value.
+const flagHashedKey uint32 = 1 << 0
+
+// hashedKeyReadable is how much of the original key is kept, cleaned up,
+// in front of the digest of a hashed key
+const hashedKeyReadable = maxKeyLength - 1 - 2*sha256.Size
+
+// legalKey reports whether memcached accepts key as is: not empty, at most
+// 250 bytes, and free of spaces and control characters
+func legalKey(key string) bool {
+	if len(key) == 0 || len(key) > maxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// itemKey maps key to the key it is stored under. Legal keys are used as
+// is; others become a readable prefix of the key, with illegal bytes
+// replaced by "_", then "#" and the hex SHA-256 of the whole key.
+func itemKey(key string) (string, bool) {
+	if legalKey(key) {
+		return key, false
+	}
+
+	// work on bytes: the key may not be valid UTF-8, and rune-based
+	// replacement could make it longer
+	readable := []byte(key[:min(len(key), hashedKeyReadable)])
+	for i, c := range readable {
+		if c <= ' ' || c == 0x7f {
+			readable[i] = '_'
+		}
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return string(readable) + "#" + hex.EncodeToString(sum[:]), true
+}
+
+// storedKey returns the key that key is stored under
+func storedKey(key string) string {
+	k, _ := itemKey(key)
+	return k
+}
+
+// keyOverhead is how many bytes newItem adds in front of the value of key
+func keyOverhead(key string) int {
+	if legalKey(key) {
+		return 0
+	}
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], uint64(len(key))) + len(key)
+}
+
+// newItem builds the item storing data for key. Under a hashed key the
+// original key is stored in front of data, so reads can detect collisions.
+func newItem(key string, data []byte, expiration int32) *memcache.Item {
+	k, hashed := itemKey(key)
+	item := &memcache.Item{Key: k, Value: data, Expiration: expiration}
+	if hashed {
+		value := binary.AppendUvarint(nil, uint64(len(key)))
+		value = append(value, key...)
+		item.Value = append(value, data...)
+		item.Flags |= flagHashedKey
+	}
+	return item
+}
+
+// itemValue returns the encoded value held by item for key. An item under
+// a hashed key that belongs to a different key is reported as a miss, and
+// so is an item written without the hashed flag, such as one set directly
+// under a key that happens to look like the hashed form of key.
+func itemValue(key string, item *memcache.Item) ([]byte, error) {
+	hashed := !legalKey(key)
+	if item.Flags&flagHashedKey == 0 {
+		if hashed {
+			return nil, cache.ErrNotFound
+		}
+		return item.Value, nil
+	}
+
+	n, size := binary.Uvarint(item.Value)
+	if size <= 0 || uint64(len(item.Value)-size) < n {
+		return nil, cache.ErrNotFound
+	}
+	if stored := string(item.Value[size : size+int(n)]); stored != key {
+		return nil, cache.ErrNotFound
+	}
+	return item.Value[size+int(n):], nil
+}
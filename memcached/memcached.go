@@ -1,18 +1,28 @@
 package memcached
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"time"
 	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
 )
 
 type MemcachedCache struct {
-	client *memcache.Client
+	client      *memcache.Client
+	codec       cache.Codec
+	namespace   string
+	maxItemSize int
 }
 
 // create a new MemcachedCache connected to the provided server addresse (localhost:11211). 
 func NewMemcachedCache(servers ...string) (*MemcachedCache, error) {
+	return NewMemcachedCacheWithOptions(servers)
+}
+
+// NewMemcachedCacheWithOptions is like NewMemcachedCache but applies opts.
+// Values are serialized with cache.JSONCodec unless WithCodec is given.
+func NewMemcachedCacheWithOptions(servers []string, opts ...Option) (*MemcachedCache, error) {
 	if len(servers) == 0 {
 		servers = []string{"localhost:11211"}
 	}
@@ -28,57 +38,164 @@ func NewMemcachedCache(servers ...string) (*MemcachedCache, error) {
 		return nil, err
 	}
 
-	return &MemcachedCache{
-		client: client,
-	}, nil
+	mc := &MemcachedCache{
+		client:      client,
+		codec:       cache.JSONCodec{},
+		maxItemSize: defaultMaxItemSize,
+	}
+	for _, opt := range opts {
+		opt(mc)
+	}
+
+	return mc, nil
 }
 
 // Get fetches a value by key.
+// Returns cache.ErrNotFound if the key is missing or has expired.
 func (mc *MemcachedCache) Get(key string) (interface{}, error) {
-	item, err := mc.client.Get(key)
-	if err == memcache.ErrCacheMiss {
-		return nil, fmt.Errorf("key not found")
+	return mc.GetCtx(context.Background(), key)
+}
+
+// GetCtx is like Get but fails once ctx is done.
+// gomemcache has no context support, so ctx is checked before and
+// after the call and the client's own Timeout bounds the call itself.
+func (mc *MemcachedCache) GetCtx(ctx context.Context, key string) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var result interface{}
+	err := mc.GetInto(key, &result)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	var result interface{}
-	if err := json.Unmarshal(item.Value, &result); err == nil {
-		return result, nil
+	return result, nil
+}
+
+// GetInto decodes the value stored under key into dst, which must be a
+// non-nil pointer, so the caller gets back the type that was stored.
+func (mc *MemcachedCache) GetInto(key string, dst interface{}) error {
+	prefix, err := mc.keyPrefix()
+	if err != nil {
+		return err
+	}
+
+	item, err := mc.client.Get(storedKey(prefix + key))
+	if err == memcache.ErrCacheMiss {
+		return cache.ErrNotFound
+	}
+	if err != nil {
+		return err
 	}
 
-	return string(item.Value), nil
+	data, err := mc.value(prefix+key, item)
+	if err != nil {
+		return err
+	}
+	if err := mc.codec.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("memcached: decode value for key %q: %w", key, err)
+	}
+	return nil
 }
 
 // Set stores a key with an optional TTL. 
+// Returns an error if the codec cannot encode value.
 func (mc *MemcachedCache) Set(key string, value interface{}, ttl time.Duration) error {
+	return mc.SetCtx(context.Background(), key, value, ttl)
+}
+
+// SetCtx is like Set but fails once ctx is done.
+func (mc *MemcachedCache) SetCtx(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	expiration := int32(0)
 	if ttl > 0 {
 		expiration = int32(ttl.Seconds())
 	}
 
-	data, err := json.Marshal(value)
+	data, err := mc.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("memcached: encode value for key %q: %w", key, err)
+	}
+
+	prefix, err := mc.keyPrefix()
 	if err != nil {
-		data = []byte(fmt.Sprintf("%v", value))
+		return err
 	}
 
-	item := &memcache.Item{
-		Key:        key,
-		Value:      data,
-		Expiration: expiration,
+	items, err := mc.newItems(prefix+key, data, expiration)
+	if err != nil {
+		return err
 	}
 
-	return mc.client.Set(item)
+	old := mc.oldChunks(prefix + key)
+	for _, item := range items {
+		if err := mc.client.Set(item); err != nil {
+			return err
+		}
+	}
+	mc.deleteChunks(old, items...)
+	return ctx.Err()
 }
 
 // Delete removes the given key from Memcached.
+// Returns cache.ErrNotFound if the key did not exist. The chunks of a
+// chunked value are removed too.
 func (mc *MemcachedCache) Delete(key string) error {
-	return mc.client.Delete(key)
+	return mc.DeleteCtx(context.Background(), key)
+}
+
+// DeleteCtx is like Delete but fails once ctx is done.
+func (mc *MemcachedCache) DeleteCtx(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	prefix, err := mc.keyPrefix()
+	if err != nil {
+		return err
+	}
+	old := mc.oldChunks(prefix + key)
+	err = mc.client.Delete(storedKey(prefix + key))
+	mc.deleteChunks(old)
+	if err == memcache.ErrCacheMiss {
+		return cache.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
 }
 
-// Clear flushes all keys from the Memcached server(s).
+// Clear removes the keys of this cache. With WithNamespace it moves the
+// namespace to a new generation, leaving other data on the servers alone;
+// without a namespace it flushes all keys from the Memcached server(s).
 func (mc *MemcachedCache) Clear() error {
+	return mc.ClearCtx(context.Background())
+}
+
+// ClearCtx is like Clear but fails once ctx is done.
+func (mc *MemcachedCache) ClearCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	flush := mc.client.FlushAll
+	if mc.namespace != "" {
+		flush = mc.nextGeneration
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// FlushAll removes every key from all servers in the pool, including keys
+// outside the namespace and keys written by other applications.
+func (mc *MemcachedCache) FlushAll() error {
 	return mc.client.FlushAll()
 }
 
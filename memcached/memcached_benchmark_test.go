@@ -129,3 +129,20 @@ func BenchmarkMemcachedAsyncMixed(b *testing.B) {
 		}
 	})
 }
+
+// Batch Get of 50 keys with one multi-get
+func BenchmarkMemcachedGetMulti(b *testing.B) {
+	mc := setupMemcachedBench(b)
+	defer mc.Close()
+
+	keys := make([]string, 50)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+		mc.Set(keys[i], "value", 5*time.Second)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mc.GetMulti(keys)
+	}
+}
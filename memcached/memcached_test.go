@@ -1,11 +1,15 @@
 package memcached
 
 import (
+	"context"
+	"errors"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 	cacheasync "github.com/dhanalakshms/multi-backend-cache-go/cache"
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
 )
 
 // helper to create new memcached instance
@@ -159,3 +163,435 @@ func TestMemcachedAsyncConcurrentWrites(t *testing.T) {
 		}
 	}
 }
+
+// context cancellation validation
+func TestMemcachedContextCancel(t *testing.T) {
+	mc := setupMemcached(t)
+	defer mc.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := mc.SetCtx(ctx, "ctx", "value", 5*time.Second); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	if _, err := mc.Get("ctx"); err == nil {
+		t.Fatal("Set with cancelled context should not store the key")
+	}
+}
+
+// shared miss/delete contract
+func TestMemcachedContract(t *testing.T) {
+	mc := setupMemcached(t)
+	defer mc.Close()
+
+	cachetest.RunContract(t, mc)
+}
+
+// typed wrapper returns the stored Go types
+func TestMemcachedTyped(t *testing.T) {
+	mc := setupMemcached(t)
+	defer mc.Close()
+
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	users := cacheasync.NewTyped[user](mc)
+	if err := users.Set("user", user{"alice", 30}, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := users.Get("user")
+	if err != nil || u != (user{"alice", 30}) {
+		t.Fatalf("Typed struct Get failed: %+v, %v", u, err)
+	}
+
+	ints := cacheasync.NewTyped[int](mc)
+	ints.Set("n", 42, 5*time.Second)
+
+	n, err := ints.Get("n")
+	if err != nil || n != 42 {
+		t.Fatalf("Typed int Get failed: %v, %v", n, err)
+	}
+}
+
+// codec selection and encode failures
+func TestMemcachedCodec(t *testing.T) {
+	mc, err := NewMemcachedCacheWithOptions([]string{"localhost:11211"}, WithCodec(cacheasync.RawCodec{}))
+	if err != nil {
+		t.Fatalf("Failed to connect to Memcached: %v", err)
+	}
+	defer mc.Close()
+
+	mc.Set("raw", []byte("bytes"), 5*time.Second)
+
+	val, err := mc.Get("raw")
+	if b, ok := val.([]byte); err != nil || !ok || string(b) != "bytes" {
+		t.Fatalf("Raw Get failed: %#v, %v", val, err)
+	}
+
+	if err := mc.Set("bad", 42, 5*time.Second); !errors.Is(err, cacheasync.ErrUnsupportedValue) {
+		t.Fatalf("Expected ErrUnsupportedValue, got %v", err)
+	}
+}
+
+// batch operations
+func TestMemcachedBatchOperations(t *testing.T) {
+	mc := setupMemcached(t)
+	defer mc.Close()
+
+	err := mc.SetMulti(map[string]interface{}{"a": "A", "b": "B"}, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vals, err := mc.GetMulti([]string{"a", "b", "missing"})
+	if err != nil || len(vals) != 2 || vals["a"] != "A" || vals["b"] != "B" {
+		t.Fatalf("GetMulti failed: %v, %v", vals, err)
+	}
+
+	if err := mc.DeleteMulti([]string{"a", "missing"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := mc.Get("a"); err == nil {
+		t.Fatal("DeleteMulti failed")
+	}
+}
+
+// read-through loading
+func TestMemcachedGetOrLoad(t *testing.T) {
+	mc := setupMemcached(t)
+	defer mc.Close()
+
+	calls := 0
+	loader := func() (interface{}, error) {
+		calls++
+		return "loaded", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		val, err := cacheasync.GetOrLoad(mc, "load", 5*time.Second, loader)
+		if err != nil || val != "loaded" {
+			t.Fatalf("GetOrLoad failed: %v, %v", val, err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("Expected 1 loader call, got %d", calls)
+	}
+}
+
+// Memcached cannot remove keys by prefix, so namespaced Clear refuses
+func TestMemcachedNamespaceClearUnsupported(t *testing.T) {
+	mc := setupMemcached(t)
+	defer mc.Close()
+
+	ns := cacheasync.Namespaced(mc, "svc:")
+	ns.Set("k", "v", 0)
+
+	if err := ns.Clear(); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("Expected errors.ErrUnsupported, got %v", err)
+	}
+	if val, err := mc.Get("svc:k"); err != nil || val != "v" {
+		t.Fatalf("Expected key kept under svc:k, got %v, %v", val, err)
+	}
+}
+
+// WithNamespace: Clear moves to a new generation and leaves other keys alone
+func TestMemcachedNamespace(t *testing.T) {
+	plain := setupMemcached(t)
+	defer plain.Close()
+
+	newCache := func(namespace string) *MemcachedCache {
+		mc, err := NewMemcachedCacheWithOptions(nil, WithNamespace(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { mc.Close() })
+		return mc
+	}
+	orders := newCache("orders")
+	users := newCache("users")
+
+	orders.Set("1", "o", 0)
+	orders.SetMulti(map[string]interface{}{"2": "o2", "3": "o3"}, time.Minute)
+	users.Set("1", "u", 0)
+	plain.Set("1", "p", 0)
+
+	got, err := orders.GetMulti([]string{"1", "2", "missing"})
+	if err != nil || len(got) != 2 || got["1"] != "o" || got["2"] != "o2" {
+		t.Fatalf("Unexpected GetMulti result: %v, %v", got, err)
+	}
+
+	if err := orders.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.Get("1"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected orders to be cleared, got %v", err)
+	}
+	if val, err := users.Get("1"); err != nil || val != "u" {
+		t.Fatalf("Expected users untouched, got %v, %v", val, err)
+	}
+	if val, err := plain.Get("1"); err != nil || val != "p" {
+		t.Fatalf("Expected plain key untouched, got %v, %v", val, err)
+	}
+
+	// a lost counter starts a fresh generation instead of reviving old ones
+	users.Set("2", "u2", 0)
+	if err := plain.Delete("users:gen"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.Get("2"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected a miss after the counter was lost, got %v", err)
+	}
+
+	// writes after Clear land in the new generation
+	orders.Set("1", "new", 0)
+	if val, err := orders.Get("1"); err != nil || val != "new" {
+		t.Fatalf("Expected new value, got %v, %v", val, err)
+	}
+
+	cachetest.RunContract(t, orders)
+
+	// FlushAll still wipes everything
+	if err := orders.FlushAll(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := plain.Get("1"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected FlushAll to remove all keys, got %v", err)
+	}
+}
+
+// Keys memcached rejects are hashed to a safe form
+func TestMemcachedUnsafeKeys(t *testing.T) {
+	mc := setupMemcached(t)
+	defer mc.Close()
+
+	long := "https://example.com/search?q=" + strings.Repeat("x", 300)
+	keys := []string{long, "key with spaces", "line\nbreak", "tab\tkey", ""}
+
+	for i, key := range keys {
+		if k, hashed := itemKey(key); !hashed || !legalKey(k) {
+			t.Fatalf("Expected %q to map to a legal hashed key, got %q", key, k)
+		}
+		if err := mc.Set(key, i, 0); err != nil {
+			t.Fatalf("Set(%q) failed: %v", key, err)
+		}
+	}
+	for i, key := range keys {
+		if val, err := mc.Get(key); err != nil || val != float64(i) {
+			t.Fatalf("Get(%q): expected %d, got %v, %v", key, i, val, err)
+		}
+	}
+
+	// multibyte and invalid UTF-8 keys must not grow past the limit
+	for _, key := range []string{
+		strings.Repeat("é", 200),
+		strings.Repeat("\xff", 300),
+		strings.Repeat("日本", 100),
+		"a" + strings.Repeat("é", 150),
+	} {
+		if k, _ := itemKey(key); !legalKey(k) {
+			t.Fatalf("Expected a legal key for %d-byte key, got %d bytes", len(key), len(k))
+		}
+		if err := mc.Set(key, "v", 0); err != nil {
+			t.Fatalf("Set of a %d-byte key failed: %v", len(key), err)
+		}
+		if val, err := mc.Get(key); err != nil || val != "v" {
+			t.Fatalf("Get of a %d-byte key: got %v, %v", len(key), val, err)
+		}
+	}
+
+	// a multi-KB key still leaves room for a value close to the item limit
+	urlKey := "https://example.com/?q=" + strings.Repeat("q", 5000)
+	nearLimit := strings.Repeat("v", defaultMaxItemSize-itemOverhead-100)
+	if err := mc.Set(urlKey, nearLimit, 0); err != nil {
+		t.Fatalf("Set with a long key and a large value failed: %v", err)
+	}
+	if val, err := mc.Get(urlKey); err != nil || val != nearLimit {
+		t.Fatalf("Expected the large value back, got %v", err)
+	}
+
+	// legal keys are stored unchanged
+	if k, hashed := itemKey("plain:key"); hashed || k != "plain:key" {
+		t.Fatalf("Expected legal key unchanged, got %q", k)
+	}
+
+	got, err := mc.GetMulti([]string{long, "key with spaces", "missing key"})
+	if err != nil || len(got) != 2 || got["key with spaces"] != float64(1) {
+		t.Fatalf("Unexpected GetMulti result: %v, %v", got, err)
+	}
+
+	if err := mc.Delete("key with spaces"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.Get("key with spaces"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected key to be deleted, got %v", err)
+	}
+
+	// an item whose stored original key differs is a miss, not a wrong value
+	impostor := newItem("some other key", []byte(`"wrong"`), 0)
+	impostor.Key = storedKey(long)
+	if err := mc.client.Set(impostor); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := mc.Get(long); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected a miss on key collision, got %v, %v", val, err)
+	}
+	if got, _ := mc.GetMulti([]string{long}); len(got) != 0 {
+		t.Fatalf("Expected GetMulti to skip the collision, got %v", got)
+	}
+
+	// so is an item set directly under the hashed form of a key
+	spoofed := storedKey("user profile 42")
+	if err := mc.Set(spoofed, "attacker", 0); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := mc.Get("user profile 42"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected a miss for an unhashed item under a hashed key, got %v, %v", val, err)
+	}
+	if got, _ := mc.GetMulti([]string{"user profile 42"}); len(got) != 0 {
+		t.Fatalf("Expected GetMulti to skip the unhashed item, got %v", got)
+	}
+	items, err := chunkItems(spoofed, []byte("chunked"), 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestCopy := items[len(items)-1]
+	manifestCopy.Key = storedKey(chunksKey("user profile 42"))
+	if err := mc.client.Set(manifestCopy); err != nil {
+		t.Fatal(err)
+	}
+	if old := mc.oldChunks("user profile 42"); len(old) != 0 {
+		t.Fatalf("Expected no chunks from an unhashed manifest, got %v", old)
+	}
+}
+
+// Values over the item size limit are chunked and reassembled
+func TestMemcachedLargeValues(t *testing.T) {
+	mc := setupMemcached(t)
+	defer mc.Close()
+
+	// beyond the default 1 MB limit
+	big := strings.Repeat("0123456789", 300_000)
+	if err := mc.Set("big", big, time.Minute); err != nil {
+		t.Fatalf("Set of a 3 MB value failed: %v", err)
+	}
+	if val, err := mc.Get("big"); err != nil || val != big {
+		t.Fatalf("Expected the 3 MB value back, got %v", err)
+	}
+
+	small, err := NewMemcachedCacheWithOptions(nil, WithMaxItemSize(4096))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer small.Close()
+
+	value := strings.Repeat("x", 20_000)
+	longKey := strings.Repeat("k", 300)
+	small.SetMulti(map[string]interface{}{"chunked": value, "plain": "p", longKey: value}, 0)
+
+	item, err := small.client.Get("chunked")
+	if err != nil || item.Flags&flagChunked == 0 {
+		t.Fatalf("Expected a manifest item, got %v", err)
+	}
+
+	got, err := small.GetMulti([]string{"chunked", "plain", longKey, "missing"})
+	if err != nil || len(got) != 3 || got["chunked"] != value || got[longKey] != value {
+		t.Fatalf("Unexpected GetMulti result: %d keys, %v", len(got), err)
+	}
+
+	// a missing chunk makes the whole value a miss
+	m, _ := parseManifest(item.Value)
+	if err := small.client.Delete(m.chunkKey("chunked", 2)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := small.Get("chunked"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected a miss with a chunk missing, got %v", err)
+	}
+	if got, _ := small.GetMulti([]string{"chunked"}); len(got) != 0 {
+		t.Fatalf("Expected GetMulti to skip the damaged value, got %d keys", len(got))
+	}
+
+	// a damaged chunk fails the digest check
+	small.Set("chunked", value, 0)
+	item, _ = small.client.Get("chunked")
+	m, _ = parseManifest(item.Value)
+	chunk, _ := small.client.Get(m.chunkKey("chunked", 0))
+	chunk.Value[0] = 'y'
+	small.client.Set(chunk)
+	if _, err := small.Get("chunked"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected a miss with a damaged chunk, got %v", err)
+	}
+
+	// overwriting with a small value drops back to a single item
+	small.Set("chunked", "tiny", 0)
+	if val, err := small.Get("chunked"); err != nil || val != "tiny" {
+		t.Fatalf("Expected tiny, got %v, %v", val, err)
+	}
+}
+
+// Overwrites and deletes remove the chunks of the old value
+func TestMemcachedLargeValueChunkCleanup(t *testing.T) {
+	mc, err := NewMemcachedCacheWithOptions(nil, WithMaxItemSize(4096))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mc.Close()
+
+	value := strings.Repeat("x", 20_000)
+	chunksOf := func(key string) []string {
+		t.Helper()
+		item, err := mc.client.Get(storedKey(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := parseManifest(item.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m.chunkKeys(key)
+	}
+	assertGone := func(chunkKeys []string) {
+		t.Helper()
+		found, err := mc.client.GetMulti(chunkKeys)
+		if err != nil || len(found) != 0 {
+			t.Fatalf("Expected old chunks removed, %d left, %v", len(found), err)
+		}
+	}
+
+	mc.Set("k", value, 0)
+	first := chunksOf("k")
+
+	mc.Set("k", value+"y", 0)
+	assertGone(first)
+	if val, err := mc.Get("k"); err != nil || val != value+"y" {
+		t.Fatalf("Expected the new value, got %v", err)
+	}
+
+	mc.SetMulti(map[string]interface{}{"k": value}, 0)
+	second := chunksOf("k")
+	mc.SetMulti(map[string]interface{}{"k": "small"}, 0)
+	assertGone(second)
+
+	// the manifest copy only exists while the value is chunked
+	assertGone([]string{storedKey(chunksKey("k"))})
+
+	mc.Set("k", value, 0)
+	third := chunksOf("k")
+	if err := mc.Delete("k"); err != nil {
+		t.Fatal(err)
+	}
+	assertGone(third)
+
+	mc.Set("k", value, 0)
+	fourth := chunksOf("k")
+	if err := mc.DeleteMulti([]string{"k"}); err != nil {
+		t.Fatal(err)
+	}
+	assertGone(append(fourth, storedKey(chunksKey("k"))))
+}
@@ -0,0 +1,91 @@
+package memcached
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// WithNamespace stores every key under namespace and a generation number,
+// as "<namespace>:<generation>:<key>". The generation is a counter kept in
+// memcached under "<namespace>:gen", so Clear only has to increment it: the
+// old entries are no longer reachable and age out on their own, and other
+// data on the servers is left alone. Every call reads the counter, which
+// costs one extra round trip.
+func WithNamespace(namespace string) Option {
+	return func(mc *MemcachedCache) {
+		mc.namespace = namespace
+	}
+}
+
+// generationKey is the key holding the namespace's generation counter
+func (mc *MemcachedCache) generationKey() string {
+	return storedKey(mc.namespace + ":gen")
+}
+
+// keyPrefix returns the prefix added to every key: empty without a
+// namespace, and the namespace and its current generation otherwise
+func (mc *MemcachedCache) keyPrefix() (string, error) {
+	if mc.namespace == "" {
+		return "", nil
+	}
+
+	gen, err := mc.generation()
+	if err != nil {
+		return "", err
+	}
+	return mc.namespace + ":" + strconv.FormatUint(gen, 10) + ":", nil
+}
+
+// generation reads the namespace's generation, starting a new one if the
+// counter is missing
+func (mc *MemcachedCache) generation() (uint64, error) {
+	for {
+		item, err := mc.client.Get(mc.generationKey())
+		if err == nil {
+			gen, err := strconv.ParseUint(string(item.Value), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("memcached: bad generation for namespace %q: %w", mc.namespace, err)
+			}
+			return gen, nil
+		}
+		if err != memcache.ErrCacheMiss {
+			return 0, err
+		}
+
+		gen, err := mc.newGeneration()
+		if err != memcache.ErrNotStored {
+			return gen, err
+		}
+		// another client created the counter first: read theirs
+	}
+}
+
+// newGeneration creates the generation counter if it does not exist. It
+// starts from the current time, so a counter recreated after memcached
+// evicted it never goes back to a generation whose entries still exist.
+func (mc *MemcachedCache) newGeneration() (uint64, error) {
+	gen := uint64(time.Now().UnixNano())
+	err := mc.client.Add(&memcache.Item{
+		Key:   mc.generationKey(),
+		Value: []byte(strconv.FormatUint(gen, 10)),
+	})
+	return gen, err
+}
+
+// nextGeneration moves the namespace to a new generation
+func (mc *MemcachedCache) nextGeneration() error {
+	for {
+		_, err := mc.client.Increment(mc.generationKey(), 1)
+		if err != memcache.ErrCacheMiss {
+			return err
+		}
+
+		// no counter: any new generation is already past the old entries
+		if _, err := mc.newGeneration(); err != memcache.ErrNotStored {
+			return err
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package memcached
+
+import "github.com/dhanalakshms/multi-backend-cache-go/cache"
+
+// Option configures a MemcachedCache at construction.
+type Option func(*MemcachedCache)
+
+// WithCodec sets the codec used to serialize values. Defaults to cache.JSONCodec.
+func WithCodec(codec cache.Codec) Option {
+	return func(mc *MemcachedCache) {
+		mc.codec = codec
+	}
+}
+
+// WithMaxItemSize sets the largest item the servers accept, as set with
+// memcached -I. Defaults to 1 MB. Larger encoded values are split into
+// chunks stored under separate keys and reassembled on Get. Sizes too
+// small to hold a chunk are ignored.
+func WithMaxItemSize(size int) Option {
+	return func(mc *MemcachedCache) {
+		if size > itemOverhead {
+			mc.maxItemSize = size
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package redisbackend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GetMulti fetches keys with MGET. On a cluster, keys are grouped by hash
+// slot and each group is fetched with its own MGET in one pipeline, since
+// a single MGET cannot span slots.
+// Missing and expired keys are left out of the result.
+func (rc *RedisCache) GetMulti(keys []string) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	ctx := context.Background()
+	groups := rc.slotGroups(rc.keys(keys))
+	cmds := make([]*redis.SliceCmd, len(groups))
+	if len(groups) == 1 {
+		cmds[0] = rc.client.MGet(ctx, groups[0]...)
+	} else {
+		pipe := rc.client.Pipeline()
+		for i, group := range groups {
+			cmds[i] = pipe.MGet(ctx, group...)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, cmd := range cmds {
+		vals, err := cmd.Result()
+		if err != nil {
+			return nil, err
+		}
+		if err := rc.decodeMulti(groups[i], vals, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// decodeMulti adds the MGET replies vals for the Redis keys redisKeys to
+// result, under their cache keys
+func (rc *RedisCache) decodeMulti(redisKeys []string, vals []interface{}, result map[string]interface{}) error {
+	for i, raw := range vals {
+		s, ok := raw.(string)
+		if !ok {
+			continue // nil reply: key missing
+		}
+
+		key := strings.TrimPrefix(redisKeys[i], rc.prefix)
+		var val interface{}
+		if err := rc.codec.Unmarshal([]byte(s), &val); err != nil {
+			return fmt.Errorf("redis: decode value for key %q: %w", key, err)
+		}
+		result[key] = val
+	}
+	return nil
+}
+
+// SetMulti stores items with the same TTL.
+// MSET cannot set expirations, so each key is written with SET in one
+// pipeline; on a cluster the pipeline sends each SET to its key's node.
+// All values are encoded before anything is sent.
+func (rc *RedisCache) SetMulti(items map[string]interface{}, ttl time.Duration) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	encoded := make(map[string][]byte, len(items))
+	for key, val := range items {
+		data, err := rc.codec.Marshal(val)
+		if err != nil {
+			return fmt.Errorf("redis: encode value for key %q: %w", key, err)
+		}
+		encoded[key] = data
+	}
+
+	ctx := context.Background()
+	pipe := rc.client.Pipeline()
+	for key, data := range encoded {
+		pipe.Set(ctx, rc.key(key), data, ttl)
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DeleteMulti removes keys with DEL, one per hash slot on a cluster.
+// Missing keys are ignored.
+func (rc *RedisCache) DeleteMulti(keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	return rc.perSlot(ctx, rc.keys(keys), func(pipe redis.Pipeliner, group []string) {
+		pipe.Del(ctx, group...)
+	})
+}
+
+// unlink removes Redis keys with UNLINK, one per hash slot on a cluster
+func (rc *RedisCache) unlink(ctx context.Context, redisKeys []string) error {
+	return rc.perSlot(ctx, redisKeys, func(pipe redis.Pipeliner, group []string) {
+		pipe.Unlink(ctx, group...)
+	})
+}
+
+// perSlot queues a command for each hash slot group of redisKeys with add
+// and runs them in one pipeline
+func (rc *RedisCache) perSlot(ctx context.Context, redisKeys []string, add func(pipe redis.Pipeliner, group []string)) error {
+	if len(redisKeys) == 0 {
+		return nil
+	}
+
+	pipe := rc.client.Pipeline()
+	for _, group := range rc.slotGroups(redisKeys) {
+		add(pipe, group)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// keys returns the Redis keys for cache keys
+func (rc *RedisCache) keys(keys []string) []string {
+	if rc.prefix == "" {
+		return keys
+	}
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = rc.key(key)
+	}
+	return redisKeys
+}
+
+// slotGroups splits keys into groups that a single multi-key command can
+// take: one group per hash slot on a cluster, and all keys otherwise.
+func (rc *RedisCache) slotGroups(keys []string) [][]string {
+	if _, ok := rc.client.(*redis.ClusterClient); !ok {
+		return [][]string{keys}
+	}
+	return groupBySlot(keys)
+}
@@ -0,0 +1,253 @@
+package redisbackend
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
+	"github.com/go-redis/redis/v8"
+)
+
+// Invalidation message ops, sent as "<origin> <op> <key>".
+const (
+	opDelete      = "del"
+	opClear       = "clear"
+	opClearPrefix = "clearprefix"
+)
+
+// resubscribeDelay is how long the listener waits after a receive error
+// before letting go-redis reconnect.
+const resubscribeDelay = 100 * time.Millisecond
+
+// Invalidator keeps a local cache in step with other processes.
+// Keys published on a Redis pub/sub channel are evicted from the local
+// cache of every other subscribed Invalidator. After the subscription is
+// lost and re-established, the local cache is cleared, since messages sent
+// in between were missed.
+type Invalidator struct {
+	client  redis.UniversalClient
+	channel string
+	id      string
+	local   cache.Cache
+	pubsub  *redis.PubSub
+
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewInvalidator subscribes to channel using the client of rc and evicts
+// keys from local as invalidations arrive from other instances.
+func NewInvalidator(rc *RedisCache, channel string, local cache.Cache) (*Invalidator, error) {
+	id, err := newInstanceID()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	pubsub := rc.client.Subscribe(ctx, channel)
+
+	// wait for the subscription to be confirmed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	inv := &Invalidator{
+		client:  rc.client,
+		channel: channel,
+		id:      id,
+		local:   local,
+		pubsub:  pubsub,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go inv.listen()
+
+	return inv, nil
+}
+
+// Invalidate tells other instances to evict key from their local cache.
+func (inv *Invalidator) Invalidate(key string) error {
+	return inv.publish(opDelete, key)
+}
+
+// InvalidateAll tells other instances to clear their local cache.
+func (inv *Invalidator) InvalidateAll() error {
+	return inv.publish(opClear, "")
+}
+
+// InvalidatePrefix tells other instances to evict every key starting with
+// prefix from their local cache. Local caches that cannot clear by prefix
+// are cleared entirely.
+func (inv *Invalidator) InvalidatePrefix(prefix string) error {
+	return inv.publish(opClearPrefix, prefix)
+}
+
+// Close unsubscribes and stops the listener. The Redis client is not closed.
+func (inv *Invalidator) Close() error {
+	var err error
+	inv.once.Do(func() {
+		close(inv.done)
+		err = inv.pubsub.Close()
+		<-inv.stopped
+	})
+	return err
+}
+
+func (inv *Invalidator) publish(op, key string) error {
+	msg := inv.id + " " + op + " " + key
+	return inv.client.Publish(context.Background(), inv.channel, msg).Err()
+}
+
+// listen applies incoming invalidations until Close is called.
+func (inv *Invalidator) listen() {
+	defer close(inv.stopped)
+
+	ctx := context.Background()
+	connected := true
+
+	for {
+		msg, err := inv.pubsub.Receive(ctx)
+		if err != nil {
+			select {
+			case <-inv.done:
+				return
+			case <-time.After(resubscribeDelay):
+			}
+			// go-redis reconnects and resubscribes on the next Receive
+			connected = false
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind == "subscribe" && !connected {
+				inv.local.Clear()
+			}
+			connected = true
+		case *redis.Message:
+			inv.apply(m.Payload)
+		}
+	}
+}
+
+// apply evicts what a message from another instance refers to.
+func (inv *Invalidator) apply(payload string) {
+	parts := strings.SplitN(payload, " ", 3)
+	if len(parts) != 3 || parts[0] == inv.id {
+		return
+	}
+
+	switch parts[1] {
+	case opDelete:
+		inv.local.Delete(parts[2])
+	case opClear:
+		inv.local.Clear()
+	case opClearPrefix:
+		if pc, ok := inv.local.(cache.PrefixClearer); ok {
+			pc.ClearPrefix(parts[2])
+		} else {
+			inv.local.Clear()
+		}
+	}
+}
+
+func newInstanceID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("redis: generate instance id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// NearCache is a cache.Tiered of a local cache over a RedisCache whose
+// writes, deletes and clears are broadcast to other NearCache instances
+// on the same channel so their local copies are evicted.
+type NearCache struct {
+	*cache.Tiered
+	inv *Invalidator
+}
+
+// NewNearCache layers local over rc and subscribes to channel.
+// l1TTL caps how long entries live in local, as in cache.NewTiered.
+func NewNearCache(local cache.Cache, rc *RedisCache, channel string, l1TTL time.Duration) (*NearCache, error) {
+	inv, err := NewInvalidator(rc, channel, local)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NearCache{
+		Tiered: cache.NewTiered(local, rc, l1TTL),
+		inv:    inv,
+	}, nil
+}
+
+// Set stores value in both tiers and invalidates key on other instances.
+func (nc *NearCache) Set(key string, value interface{}, ttl time.Duration) error {
+	if err := nc.Tiered.Set(key, value, ttl); err != nil {
+		return err
+	}
+	return nc.inv.Invalidate(key)
+}
+
+// Delete removes key from both tiers and invalidates it on other instances.
+func (nc *NearCache) Delete(key string) error {
+	err := nc.Tiered.Delete(key)
+	if pubErr := nc.inv.Invalidate(key); pubErr != nil && err == nil {
+		return pubErr
+	}
+	return err
+}
+
+// Clear empties both tiers and clears the local cache of other instances.
+func (nc *NearCache) Clear() error {
+	if err := nc.Tiered.Clear(); err != nil {
+		return err
+	}
+	return nc.inv.InvalidateAll()
+}
+
+// ClearPrefix removes keys starting with prefix from both tiers and from
+// the local cache of other instances, so cache.Namespaced can clear a
+// namespace of a NearCache.
+func (nc *NearCache) ClearPrefix(prefix string) error {
+	if err := nc.Tiered.ClearPrefix(prefix); err != nil {
+		return err
+	}
+	return nc.inv.InvalidatePrefix(prefix)
+}
+
+// SetMulti stores items in both tiers and invalidates them on other instances.
+func (nc *NearCache) SetMulti(items map[string]interface{}, ttl time.Duration) error {
+	if err := nc.Tiered.SetMulti(items, ttl); err != nil {
+		return err
+	}
+	for key := range items {
+		if err := nc.inv.Invalidate(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteMulti removes keys from both tiers and invalidates them on other instances.
+func (nc *NearCache) DeleteMulti(keys []string) error {
+	err := nc.Tiered.DeleteMulti(keys)
+	for _, key := range keys {
+		if pubErr := nc.inv.Invalidate(key); pubErr != nil && err == nil {
+			err = pubErr
+		}
+	}
+	return err
+}
+
+// Close stops listening for invalidations. The RedisCache is not closed.
+func (nc *NearCache) Close() error {
+	return nc.inv.Close()
+}
@@ -0,0 +1,158 @@
+package redisbackend
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/dhanalakshms/multi-backend-cache-go/cache"
+	"github.com/go-redis/redis/v8"
+)
+
+// Option configures a RedisCache at construction.
+type Option func(*RedisCache)
+
+// WithCodec sets the codec used to serialize values. Defaults to cache.JSONCodec.
+func WithCodec(codec cache.Codec) Option {
+	return func(rc *RedisCache) {
+		rc.codec = codec
+	}
+}
+
+// WithPrefix stores every key under prefix, such as "myapp:", so the cache
+// can share a database with other data. Clear then only removes keys under
+// the prefix; FlushDB still removes everything.
+func WithPrefix(prefix string) Option {
+	return func(rc *RedisCache) {
+		rc.prefix = prefix
+	}
+}
+
+// RedisOptions configures the connection used by NewRedisCacheWithOptions.
+// Zero values fall back to the go-redis defaults.
+type RedisOptions struct {
+	// Addr is the host:port of the server. Defaults to localhost:6379.
+	Addr string
+
+	// Username and Password authenticate the connection; Username is only
+	// needed for Redis 6 ACL users.
+	Username string
+	Password string
+
+	// DB is the database index selected after connecting.
+	DB int
+
+	// TLSConfig enables TLS when set.
+	TLSConfig *tls.Config
+
+	// PoolSize is the maximum number of connections; MinIdleConns keeps
+	// that many open while idle.
+	PoolSize     int
+	MinIdleConns int
+
+	// Timeouts for establishing a connection and for each read and write.
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// MaxRetries is how often a failed command is retried; -1 disables
+	// retries. Retries back off between MinRetryBackoff and MaxRetryBackoff.
+	MaxRetries      int
+	MinRetryBackoff time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// clientOptions converts o to the go-redis client options
+func (o RedisOptions) clientOptions() *redis.Options {
+	return &redis.Options{
+		Addr:            o.Addr,
+		Username:        o.Username,
+		Password:        o.Password,
+		DB:              o.DB,
+		TLSConfig:       o.TLSConfig,
+		PoolSize:        o.PoolSize,
+		MinIdleConns:    o.MinIdleConns,
+		DialTimeout:     o.DialTimeout,
+		ReadTimeout:     o.ReadTimeout,
+		WriteTimeout:    o.WriteTimeout,
+		MaxRetries:      o.MaxRetries,
+		MinRetryBackoff: o.MinRetryBackoff,
+		MaxRetryBackoff: o.MaxRetryBackoff,
+	}
+}
+
+// ClusterOptions configures the connection used by NewRedisClusterCache.
+// The embedded RedisOptions apply to every node; Addr and DB are ignored,
+// since a cluster only has database 0.
+type ClusterOptions struct {
+	RedisOptions
+
+	// Addrs are seed nodes used to discover the rest of the cluster.
+	Addrs []string
+
+	// MaxRedirects is how often a command follows MOVED and ASK replies.
+	// Defaults to 3.
+	MaxRedirects int
+
+	// ReadOnly allows reads to be served by replicas.
+	ReadOnly bool
+}
+
+// clientOptions converts o to the go-redis cluster options
+func (o ClusterOptions) clientOptions() *redis.ClusterOptions {
+	return &redis.ClusterOptions{
+		Addrs:           o.Addrs,
+		MaxRedirects:    o.MaxRedirects,
+		ReadOnly:        o.ReadOnly,
+		Username:        o.Username,
+		Password:        o.Password,
+		TLSConfig:       o.TLSConfig,
+		PoolSize:        o.PoolSize,
+		MinIdleConns:    o.MinIdleConns,
+		DialTimeout:     o.DialTimeout,
+		ReadTimeout:     o.ReadTimeout,
+		WriteTimeout:    o.WriteTimeout,
+		MaxRetries:      o.MaxRetries,
+		MinRetryBackoff: o.MinRetryBackoff,
+		MaxRetryBackoff: o.MaxRetryBackoff,
+	}
+}
+
+// SentinelOptions configures the connection used by NewRedisSentinelCache.
+// The embedded RedisOptions apply to the master connection; Addr is
+// ignored, since the master is looked up through the Sentinels.
+type SentinelOptions struct {
+	RedisOptions
+
+	// MasterName is the name the Sentinels monitor the master under.
+	MasterName string
+
+	// SentinelAddrs are the host:port addresses of the Sentinels.
+	SentinelAddrs []string
+
+	// SentinelUsername and SentinelPassword authenticate with the
+	// Sentinels, when they require it.
+	SentinelUsername string
+	SentinelPassword string
+}
+
+// clientOptions converts o to the go-redis failover options
+func (o SentinelOptions) clientOptions() *redis.FailoverOptions {
+	return &redis.FailoverOptions{
+		MasterName:       o.MasterName,
+		SentinelAddrs:    o.SentinelAddrs,
+		SentinelUsername: o.SentinelUsername,
+		SentinelPassword: o.SentinelPassword,
+		Username:         o.Username,
+		Password:         o.Password,
+		DB:               o.DB,
+		TLSConfig:        o.TLSConfig,
+		PoolSize:         o.PoolSize,
+		MinIdleConns:     o.MinIdleConns,
+		DialTimeout:      o.DialTimeout,
+		ReadTimeout:      o.ReadTimeout,
+		WriteTimeout:     o.WriteTimeout,
+		MaxRetries:       o.MaxRetries,
+		MinRetryBackoff:  o.MinRetryBackoff,
+		MaxRetryBackoff:  o.MaxRetryBackoff,
+	}
+}
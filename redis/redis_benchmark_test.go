@@ -130,3 +130,20 @@ func BenchmarkRedisAsyncMixed(b *testing.B) {
 		}
 	})
 }
+
+// Batch Get of 50 keys with one MGET
+func BenchmarkRedisGetMulti(b *testing.B) {
+	rc := setupRedisBench(b)
+	defer rc.Close()
+
+	keys := make([]string, 50)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+		rc.Set(keys[i], "value", 5*time.Second)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rc.GetMulti(keys)
+	}
+}
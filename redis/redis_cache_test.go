@@ -1,11 +1,17 @@
 package redisbackend
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 	cacheasync "github.com/dhanalakshms/multi-backend-cache-go/cache"
+	"github.com/dhanalakshms/multi-backend-cache-go/cache/cachetest"
+	"github.com/dhanalakshms/multi-backend-cache-go/inmemory"
 )
 
 // Create Redis connection and start with empty DB
@@ -141,3 +147,588 @@ func TestRedisAsyncOperations(t *testing.T) {
 		t.Fatal("Async delete failed")
 	}
 }
+
+// Context deadline is passed through to Redis
+func TestRedisContextDeadline(t *testing.T) {
+	rc := setupRedis(t)
+	defer rc.Close()
+
+	if err := rc.SetCtx(context.Background(), "ctx", "value", 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+
+	if _, err := rc.GetCtx(ctx, "ctx"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+// Shared miss/delete contract
+func TestRedisContract(t *testing.T) {
+	rc := setupRedis(t)
+	defer rc.Close()
+
+	cachetest.RunContract(t, rc)
+}
+
+// Typed wrapper returns the stored Go types
+func TestRedisTyped(t *testing.T) {
+	rc := setupRedis(t)
+	defer rc.Close()
+
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	users := cacheasync.NewTyped[user](rc)
+	if err := users.Set("user", user{"alice", 30}, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := users.Get("user")
+	if err != nil || u != (user{"alice", 30}) {
+		t.Fatalf("Typed struct Get failed: %+v, %v", u, err)
+	}
+
+	ints := cacheasync.NewTyped[int](rc)
+	ints.Set("n", 42, 5*time.Second)
+
+	n, err := ints.Get("n")
+	if err != nil || n != 42 {
+		t.Fatalf("Typed int Get failed: %v, %v", n, err)
+	}
+}
+
+// Codec selection and encode failures
+func TestRedisCodec(t *testing.T) {
+	rc, err := NewRedisCache("localhost:6379", WithCodec(cacheasync.MsgpackCodec{}))
+	if err != nil {
+		t.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer rc.Close()
+
+	rc.Set("num", "123", 5*time.Second)
+
+	val, err := rc.Get("num")
+	if err != nil || val != "123" {
+		t.Fatalf("Expected string \"123\", got %#v, %v", val, err)
+	}
+
+	if err := rc.Set("bad", make(chan int), 5*time.Second); err == nil {
+		t.Fatal("Expected encode error")
+	}
+}
+
+// Batch operations
+func TestRedisBatchOperations(t *testing.T) {
+	rc := setupRedis(t)
+	defer rc.Close()
+
+	err := rc.SetMulti(map[string]interface{}{"a": "A", "b": "B"}, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vals, err := rc.GetMulti([]string{"a", "b", "missing"})
+	if err != nil || len(vals) != 2 || vals["a"] != "A" || vals["b"] != "B" {
+		t.Fatalf("GetMulti failed: %v, %v", vals, err)
+	}
+
+	if err := rc.DeleteMulti([]string{"a", "missing"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rc.Get("a"); err == nil {
+		t.Fatal("DeleteMulti failed")
+	}
+}
+
+// Read-through loading
+func TestRedisGetOrLoad(t *testing.T) {
+	rc := setupRedis(t)
+	defer rc.Close()
+
+	calls := 0
+	loader := func() (interface{}, error) {
+		calls++
+		return "loaded", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		val, err := cacheasync.GetOrLoad(rc, "load", 5*time.Second, loader)
+		if err != nil || val != "loaded" {
+			t.Fatalf("GetOrLoad failed: %v, %v", val, err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("Expected 1 loader call, got %d", calls)
+	}
+}
+
+// LRU in front of Redis
+func TestRedisTiered(t *testing.T) {
+	rc := setupRedis(t)
+	defer rc.Close()
+
+	l1 := inmemory.NewLRUCache(10)
+	tiered := cacheasync.NewTiered(l1, rc, time.Second)
+
+	if err := tiered.Set("tiered", "value", 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// drop the local copy so the read falls through to Redis
+	l1.Delete("tiered")
+
+	val, err := tiered.Get("tiered")
+	if err != nil || val != "value" {
+		t.Fatalf("Tiered Get failed: %v, %v", val, err)
+	}
+
+	if err := tiered.Delete("tiered"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rc.Get("tiered"); err == nil {
+		t.Fatal("Delete did not reach Redis")
+	}
+}
+
+// Typed reads through a Tiered keep their type whether L1 is cold or warm
+func TestRedisTieredTyped(t *testing.T) {
+	rc := setupRedis(t)
+	defer rc.Close()
+
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	l1 := inmemory.NewLRUCache(10)
+	tiered := cacheasync.NewTiered(l1, rc, time.Minute)
+	users := cacheasync.NewTyped[user](tiered)
+
+	// only in Redis: the cold read decodes into user and backfills L1
+	rc.Set("user", user{"alice", 30}, time.Minute)
+	if u, err := users.Get("user"); err != nil || u != (user{"alice", 30}) {
+		t.Fatalf("Cold typed Get failed: %+v, %v", u, err)
+	}
+	if val, err := l1.Get("user"); err != nil || val != (user{"alice", 30}) {
+		t.Fatalf("Expected L1 backfilled with the typed value, got %T, %v", val, err)
+	}
+	if u, err := users.Get("user"); err != nil || u != (user{"alice", 30}) {
+		t.Fatalf("Warm typed Get failed: %+v, %v", u, err)
+	}
+
+	// an untyped read left a generic map in L1: typed reads still work
+	l1.Delete("user")
+	if _, err := tiered.Get("user"); err != nil {
+		t.Fatal(err)
+	}
+	if u, err := users.Get("user"); err != nil || u != (user{"alice", 30}) {
+		t.Fatalf("Typed Get after an untyped backfill failed: %+v, %v", u, err)
+	}
+
+	if n, err := cacheasync.NewTyped[int](tiered).Get("missing"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v, %v", n, err)
+	}
+}
+
+// Near caches on two instances stay in step via pub/sub
+func TestRedisNearCacheInvalidation(t *testing.T) {
+	rc := setupRedis(t)
+	defer rc.Close()
+
+	localA := inmemory.NewLRUCache(10)
+	localB := inmemory.NewLRUCache(10)
+
+	a, err := NewNearCache(localA, rc, "invalidation-test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	b, err := NewNearCache(localB, rc, "invalidation-test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	// B reads the key and keeps a local copy
+	a.Set("shared", "v1", 5*time.Second)
+	if val, err := b.Get("shared"); err != nil || val != "v1" {
+		t.Fatalf("Initial Get failed: %v, %v", val, err)
+	}
+
+	// A overwrites it; B's local copy must be evicted
+	a.Set("shared", "v2", 5*time.Second)
+	waitForMiss(t, localB, "shared")
+
+	if val, err := b.Get("shared"); err != nil || val != "v2" {
+		t.Fatalf("Expected fresh value, got %v, %v", val, err)
+	}
+
+	// A deletes it; B's local copy must be evicted again
+	a.Delete("shared")
+	waitForMiss(t, localB, "shared")
+
+	// Clear on A empties B's local cache
+	localB.Set("local-only", "x", 0)
+	a.Clear()
+	waitForMiss(t, localB, "local-only")
+}
+
+// Clearing a namespace of a near cache reaches the other instances
+func TestRedisNearCacheNamespaceClear(t *testing.T) {
+	rc, err := NewRedisCache("localhost:6379", WithPrefix("near:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	rc.FlushDB()
+
+	localA := inmemory.NewLRUCache(10)
+	localB := inmemory.NewLRUCache(10)
+
+	a, err := NewNearCache(localA, rc, "namespace-invalidation-test", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	b, err := NewNearCache(localB, rc, "namespace-invalidation-test", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	// written straight to Redis, so no invalidation races B's reads;
+	// B reads both keys and keeps local copies
+	rc.Set("svc:1", "s", 5*time.Second)
+	rc.Set("other:1", "o", 5*time.Second)
+	for _, key := range []string{"svc:1", "other:1"} {
+		if _, err := b.Get(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := cacheasync.Namespaced(a, "svc:").Clear(); err != nil {
+		t.Fatal(err)
+	}
+	waitForMiss(t, localB, "svc:1")
+
+	if val, err := localB.Get("other:1"); err != nil || val != "o" {
+		t.Fatalf("Expected other namespace kept in B's local cache, got %v, %v", val, err)
+	}
+	if _, err := rc.Get("svc:1"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected svc:1 removed from Redis, got %v", err)
+	}
+}
+
+// A lost subscription flushes the local cache once it is re-established
+func TestRedisNearCacheReconnect(t *testing.T) {
+	rc := setupRedis(t)
+	defer rc.Close()
+
+	// B talks to Redis through a proxy so its connections can be cut
+	proxy := startProxy(t, "localhost:6379")
+	rcB, err := NewRedisCache(proxy.addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rcB.Close()
+
+	localA := inmemory.NewLRUCache(10)
+	localB := inmemory.NewLRUCache(10)
+
+	a, err := NewNearCache(localA, rc, "reconnect-test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	b, err := NewNearCache(localB, rcB, "reconnect-test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	localB.Set("stale", "x", 0)
+
+	// invalidations sent while B is disconnected are missed, so B must
+	// flush its local cache after resubscribing
+	proxy.dropAll()
+	waitForMiss(t, localB, "stale")
+
+	// the new subscription delivers invalidations again
+	a.Set("shared", "v1", 5*time.Second)
+	if val, err := b.Get("shared"); err != nil || val != "v1" {
+		t.Fatalf("Get after reconnect failed: %v, %v", val, err)
+	}
+	a.Set("shared", "v2", 5*time.Second)
+	waitForMiss(t, localB, "shared")
+}
+
+// tcpProxy forwards connections to a Redis server and can cut them all
+type tcpProxy struct {
+	ln     net.Listener
+	target string
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func startProxy(t *testing.T, target string) *tcpProxy {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &tcpProxy{ln: ln, target: target}
+	t.Cleanup(func() {
+		ln.Close()
+		p.dropAll()
+	})
+
+	go func() {
+		for {
+			client, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			server, err := net.Dial("tcp", target)
+			if err != nil {
+				client.Close()
+				continue
+			}
+
+			p.mu.Lock()
+			p.conns = append(p.conns, client, server)
+			p.mu.Unlock()
+
+			go io.Copy(server, client)
+			go io.Copy(client, server)
+		}
+	}()
+	return p
+}
+
+func (p *tcpProxy) addr() string { return p.ln.Addr().String() }
+
+// dropAll closes every connection made through the proxy so far
+func (p *tcpProxy) dropAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, c := range p.conns {
+		c.Close()
+	}
+	p.conns = nil
+}
+
+// waitForMiss polls until key is gone from c
+func waitForMiss(t *testing.T, c cacheasync.Cache, key string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := c.Get(key); err != nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Key %q was not invalidated", key)
+}
+
+// Connection options: DB index, pool settings and URL form
+func TestRedisConnectionOptions(t *testing.T) {
+	rc, err := NewRedisCacheWithOptions(RedisOptions{
+		Addr:         "localhost:6379",
+		DB:           1,
+		PoolSize:     4,
+		DialTimeout:  time.Second,
+		ReadTimeout:  time.Second,
+		WriteTimeout: time.Second,
+		MaxRetries:   2,
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect with options: %v", err)
+	}
+	defer rc.Close()
+	rc.Clear()
+
+	if err := rc.Set("db1", "value", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// DB 0 does not see keys of DB 1
+	db0 := setupRedis(t)
+	defer db0.Close()
+	if _, err := db0.Get("db1"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected key to be missing in DB 0, got %v", err)
+	}
+
+	fromURL, err := NewRedisCacheFromURL("redis://localhost:6379/1")
+	if err != nil {
+		t.Fatalf("Failed to connect from URL: %v", err)
+	}
+	defer fromURL.Close()
+	if val, err := fromURL.Get("db1"); err != nil || val != "value" {
+		t.Fatalf("Expected value from DB 1, got %v, %v", val, err)
+	}
+
+	if _, err := NewRedisCacheFromURL("http://localhost:6379"); err == nil {
+		t.Fatal("Expected error for a non-redis URL")
+	}
+
+	// the constructor still pings, so bad credentials fail up front
+	if _, err := NewRedisCacheWithOptions(RedisOptions{Addr: "localhost:6379", Password: "wrong"}); err == nil {
+		t.Fatal("Expected error for a wrong password")
+	}
+}
+
+// Cluster mode: batch ops across slots and a prefixed Clear on every master
+func TestRedisCluster(t *testing.T) {
+	rc, err := NewRedisClusterCache(ClusterOptions{Addrs: []string{"localhost:6379"}}, WithPrefix("cluster:"))
+	if err != nil {
+		t.Skipf("Redis at localhost:6379 is not in cluster mode: %v", err)
+	}
+	defer rc.Close()
+	rc.Clear()
+
+	items := map[string]interface{}{"foo": "1", "bar": "2", "{tag}a": "3", "{tag}b": "4"}
+	if err := rc.SetMulti(items, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := rc.GetMulti([]string{"foo", "bar", "{tag}a", "{tag}b", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 || got["foo"] != "1" || got["{tag}b"] != "4" {
+		t.Fatalf("Unexpected GetMulti result: %v", got)
+	}
+
+	if err := rc.DeleteMulti([]string{"foo", "{tag}a"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rc.Get("foo"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected foo to be deleted, got %v", err)
+	}
+
+	if err := rc.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rc.Get("bar"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected bar to be cleared, got %v", err)
+	}
+}
+
+// Sentinel mode fails up front when no Sentinel is reachable
+func TestRedisSentinelUnreachable(t *testing.T) {
+	_, err := NewRedisSentinelCache(SentinelOptions{
+		MasterName:    "mymaster",
+		SentinelAddrs: []string{"localhost:1"},
+		RedisOptions:  RedisOptions{DialTimeout: 100 * time.Millisecond, MaxRetries: -1},
+	})
+	if err == nil {
+		t.Fatal("Expected error without a reachable Sentinel")
+	}
+}
+
+// WithPrefix: keys are namespaced and Clear only removes the namespace
+func TestRedisPrefix(t *testing.T) {
+	shared := setupRedis(t)
+	defer shared.Close()
+
+	newCache := func(prefix string) *RedisCache {
+		rc, err := NewRedisCache("localhost:6379", WithPrefix(prefix))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { rc.Close() })
+		return rc
+	}
+	// glob characters in the prefix must not match other keys
+	app := newCache("app[1]*:")
+	other := newCache("app1x:")
+
+	if err := app.Set("k", "v", 0); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := shared.Get("app[1]*:k"); err != nil || val != "v" {
+		t.Fatalf("Expected key stored under the prefix, got %v, %v", val, err)
+	}
+
+	// more keys than one SCAN batch
+	items := make(map[string]interface{})
+	for i := 0; i < 3*clearBatchSize; i++ {
+		items["key"+strconv.Itoa(i)] = i
+	}
+	if err := app.SetMulti(items, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	other.Set("k", "other", 0)
+	shared.Set("unrelated", "x", 0)
+
+	got, err := app.GetMulti([]string{"k", "key7", "missing"})
+	if err != nil || len(got) != 2 || got["k"] != "v" {
+		t.Fatalf("Unexpected GetMulti result: %v, %v", got, err)
+	}
+	if err := app.DeleteMulti([]string{"key7"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Get("key7"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected key7 to be deleted, got %v", err)
+	}
+
+	if err := app.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	n, _ := shared.client.DBSize(context.Background()).Result()
+	if n != 2 {
+		t.Fatalf("Expected only the 2 keys outside the prefix to remain, got %d", n)
+	}
+	if val, err := other.Get("k"); err != nil || val != "other" {
+		t.Fatalf("Expected other namespace untouched, got %v, %v", val, err)
+	}
+
+	// FlushDB still wipes everything
+	if err := app.FlushDB(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := shared.Get("unrelated"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected FlushDB to remove all keys, got %v", err)
+	}
+}
+
+// Namespaced views over Redis clear only their own keys
+func TestRedisNamespaceClear(t *testing.T) {
+	rc, err := NewRedisCache("localhost:6379", WithPrefix("app:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	rc.FlushDB()
+
+	orders := cacheasync.Namespaced(rc, "orders:")
+	users := cacheasync.Namespaced(rc, "users:")
+	orders.Set("1", "o", 0)
+	users.Set("1", "u", 0)
+
+	if err := orders.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.Get("1"); !errors.Is(err, cacheasync.ErrNotFound) {
+		t.Fatalf("Expected orders to be cleared, got %v", err)
+	}
+	if val, err := rc.Get("users:1"); err != nil || val != "u" {
+		t.Fatalf("Expected users untouched, got %v, %v", val, err)
+	}
+}
@@ -0,0 +1,52 @@
+package redisbackend
+
+import "strings"
+
+// slotCount is the number of hash slots in a Redis Cluster
+const slotCount = 16384
+
+// hashSlot returns the cluster hash slot of key: CRC16 of the key, or of
+// its hash tag (the part between the first "{" and the next "}", if not
+// empty), modulo 16384.
+func hashSlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key)) % slotCount
+}
+
+// crc16 implements CRC-16/XMODEM, the checksum used for cluster slots
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for b := 0; b < 8; b++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// groupBySlot splits keys by hash slot, keeping the order in which each
+// slot is first seen and the order of keys within a slot
+func groupBySlot(keys []string) [][]string {
+	index := make(map[int]int)
+	var groups [][]string
+	for _, key := range keys {
+		slot := hashSlot(key)
+		i, ok := index[slot]
+		if !ok {
+			i = len(groups)
+			index[slot] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], key)
+	}
+	return groups
+}
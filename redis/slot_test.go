@@ -0,0 +1,38 @@
+package redisbackend
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestHashSlot checks slots against values computed by Redis CLUSTER KEYSLOT
+func TestHashSlot(t *testing.T) {
+
+	cases := map[string]int{
+		"":                     0,
+		"123456789":            12739,
+		"foo":                  12182,
+		"bar":                  5061,
+		"{user1000}.following": 3443,
+		"{user1000}.followers": 3443,
+		"user1000":             3443,
+		"foo{}{bar}":           8363, // empty tag: whole key is hashed
+		"foo{{bar}}zap":        4015, // tag is "{bar"
+		"foo{bar}{zap}":        5061, // only the first tag counts
+	}
+	for key, want := range cases {
+		if got := hashSlot(key); got != want {
+			t.Errorf("hashSlot(%q) = %d, want %d", key, got, want)
+		}
+	}
+}
+
+// TestGroupBySlot checks keys sharing a hash tag end up in one group
+func TestGroupBySlot(t *testing.T) {
+
+	keys := []string{"{a}1", "foo", "{a}2", "bar", "{a}3"}
+	want := [][]string{{"{a}1", "{a}2", "{a}3"}, {"foo"}, {"bar"}}
+	if got := groupBySlot(keys); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+}